@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -21,7 +23,26 @@ type Logger interface {
 	Errorf(format string, v ...interface{})
 }
 
+// LogLevel controls which messages the client sends to its Logger.
+type LogLevel int
+
+const (
+	// LogLevelError logs only failures.
+	LogLevelError LogLevel = iota
+	// LogLevelWarn adds warnings such as retries and suspicious input.
+	LogLevelWarn
+	// LogLevelInfo adds one line per request. This is the default.
+	LogLevelInfo
+	// LogLevelDebug logs everything.
+	LogLevelDebug
+)
+
 // Hook defines an interface for intercepting client operations.
+//
+// Hooks run synchronously in the order they were registered with WithHooks,
+// for both PreRequest and PostRequest, so a hook registered earlier (a metrics
+// hook, say) always sees a request before one registered later (a logging
+// hook). Every request that reaches PreRequest also reaches PostRequest.
 type Hook interface {
 	// PreRequest is called before an HTTP request is made.
 	// The body parameter may be nil if there is no body.
@@ -48,7 +69,35 @@ type Client struct {
 	baseHost   string
 	httpclient *http.Client
 	logger     Logger
+	logLevel   LogLevel
 	hooks      []Hook // New field
+
+	amountValidation AmountValidationMode
+	tokenInfos       tokenInfoCache
+
+	maxCheckpointAge uint64
+	latestCheckpoint atomic.Uint64
+	checkpointCache  checkpointCache
+	checkpointOffset uint64
+
+	feeSchedule feeScheduleCache
+
+	checkTransferLocks bool
+
+	headerCallback ResponseHeaderCallback
+	headerNames    []string
+
+	retryBudget *RetryBudget
+
+	failover *failover
+	resolver *hostResolver
+
+	escalatingTimeout *escalatingTimeout
+
+	maxRequestBodySize  int64
+	maxResponseBodySize int64
+
+	clientName string
 }
 
 func PrivateKeyToAddress(privateKeyHex string) (string, error) {
@@ -72,10 +121,14 @@ func newClientInternal(baseHost string, options ...ClientOption) *Client {
 			Timeout: 4 * time.Second,
 		},
 		// logger is nil by default
+		logLevel: LogLevelInfo,
 	}
 	for _, opt := range options {
 		opt(client)
 	}
+	if client.clientName != "" && client.logger != nil {
+		client.logger = newNamedLogger(client.logger, client.clientName)
+	}
 	return client
 }
 
@@ -117,19 +170,55 @@ func WithLogger(logger Logger) ClientOption {
 	}
 }
 
-// WithHooks adds hook implementations to the Client.
+// WithLogLevel sets the most verbose level the client logs at. Use LogLevelWarn or
+// LogLevelError to drop the per-request Infof lines at high request rates.
+func WithLogLevel(level LogLevel) ClientOption {
+	return func(c *Client) {
+		c.logLevel = level
+	}
+}
+
+// logEnabled reports whether a message at level should be sent to the logger.
+func (client *Client) logEnabled(level LogLevel) bool {
+	return client.logger != nil && level <= client.logLevel
+}
+
+// WithHooks adds hook implementations to the Client. Hooks are appended and
+// called in registration order.
 func WithHooks(hooks ...Hook) ClientOption {
 	return func(c *Client) {
 		c.hooks = append(c.hooks, hooks...)
 	}
 }
 
+// Ping verifies that the node at the client's base host is reachable and
+// responds like a 1Money node. It queries the chain id endpoint, which is cheap
+// and always available, and fails if the node does not report a chain id.
+func (client *Client) Ping(ctx context.Context) error {
+	result, err := client.GetChainId(ctx)
+	if err != nil {
+		return fmt.Errorf("ping %s failed: %w", client.ActiveURL(), err)
+	}
+	if result.ChainId == 0 {
+		return fmt.Errorf("ping %s failed: node did not report a chain id", client.ActiveURL())
+	}
+	return nil
+}
+
 // GetMethod executes a GET request to the specified path and decodes the JSON response into the result.
 // The result parameter must be a pointer to a Go value suitable for JSON unmarshalling.
 // It uses `any` because the actual type of the response varies depending on the API endpoint.
 func (client *Client) GetMethod(ctx context.Context, path string, result interface{}) error {
-	fullURL := client.baseHost + path
-	if client.logger != nil {
+	ctx = client.withClientName(ctx)
+	client.recordRequest(ctx)
+	return client.withFailover(ctx, "GET", func(host string) error {
+		return client.getMethod(ctx, host, path, result)
+	})
+}
+
+func (client *Client) getMethod(ctx context.Context, host, path string, result interface{}) error {
+	fullURL := host + path
+	if client.logEnabled(LogLevelInfo) {
 		client.logger.Infof("GET %s", fullURL)
 	}
 
@@ -141,7 +230,7 @@ func (client *Client) GetMethod(ctx context.Context, path string, result interfa
 
 	req, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
-		if client.logger != nil {
+		if client.logEnabled(LogLevelError) {
 			client.logger.Errorf("Failed to create request for GET %s: %v", fullURL, err)
 		}
 		// Call PostRequest hooks even if NewRequestWithContext fails (though resp is nil)
@@ -153,20 +242,23 @@ func (client *Client) GetMethod(ctx context.Context, path string, result interfa
 		}
 		return fmt.Errorf("failed to create request: %w", err)
 	}
+	client.setClientNameHeader(req)
+	setRequestOptionHeaders(ctx, req)
 
-	resp, err := client.httpclient.Do(req)
+	resp, err := client.httpClientFor(ctx).Do(req)
 	if err != nil {
-		if client.logger != nil {
-			client.logger.Errorf("API GET request to %s failed: %v", fullURL, err)
+		netErr := &NetworkError{Kind: ClassifyNetworkError(err), Method: "GET", Path: path, Err: err}
+		if client.logEnabled(LogLevelError) {
+			client.logger.Errorf("API GET request to %s failed (%s): %v", fullURL, netErr.Kind, err)
 		}
 		// Call PostRequest hooks if client.httpclient.Do fails
 		if len(client.hooks) > 0 {
 			for _, hook := range client.hooks {
 				// Pass nil for responseBody as there's no response, and err for the error
-				hook.PostRequest(ctx, "GET", fullURL, 0, nil, err)
+				hook.PostRequest(ctx, "GET", fullURL, 0, nil, netErr)
 			}
 		}
-		return fmt.Errorf("api get failed to request path: %s, err: %w", path, err)
+		return netErr
 	}
 	return client.handleAPIResponse(ctx, "GET", fullURL, resp, result)
 }
@@ -177,24 +269,43 @@ func (client *Client) GetMethod(ctx context.Context, path string, result interfa
 // The result parameter must be a pointer to a Go value suitable for JSON unmarshalling.
 // Both use `any` because the actual types vary depending on the API endpoint and request data.
 func (client *Client) PostMethod(ctx context.Context, path string, body interface{}, result interface{}) error {
-	fullURL := client.baseHost + path
-	if client.logger != nil {
+	ctx = client.withClientName(ctx)
+	client.recordRequest(ctx)
+	return client.withFailover(ctx, "POST", func(host string) error {
+		return client.postMethod(ctx, host, path, body, result)
+	})
+}
+
+func (client *Client) postMethod(ctx context.Context, host, path string, body interface{}, result interface{}) error {
+	fullURL := host + path
+	if client.logEnabled(LogLevelInfo) {
 		client.logger.Infof("POST %s", fullURL)
 	}
 
 	data, err := json.Marshal(body)
 	if err != nil {
-		if client.logger != nil {
+		if client.logEnabled(LogLevelError) {
 			client.logger.Errorf("Failed to marshal request for POST %s: %v", fullURL, err)
 		}
-		// Call PostRequest hooks if json.Marshal fails
+		err = fmt.Errorf("failed to marshal request: %w", err)
+		// Call PostRequest hooks if json.Marshal fails, with the error that is returned
+		if len(client.hooks) > 0 {
+			for _, hook := range client.hooks {
+				hook.PostRequest(ctx, "POST", fullURL, 0, nil, err)
+			}
+		}
+		return err
+	}
+	if err := client.checkRequestSize(data); err != nil {
+		if client.logEnabled(LogLevelError) {
+			client.logger.Errorf("Refusing POST %s: %v", fullURL, err)
+		}
 		if len(client.hooks) > 0 {
 			for _, hook := range client.hooks {
-				// Pass data (which might be nil or partially formed) and err
 				hook.PostRequest(ctx, "POST", fullURL, 0, nil, err)
 			}
 		}
-		return fmt.Errorf("failed to marshal request: %w", err)
+		return err
 	}
 
 	if len(client.hooks) > 0 {
@@ -205,7 +316,7 @@ func (client *Client) PostMethod(ctx context.Context, path string, body interfac
 
 	req, err := http.NewRequestWithContext(ctx, "POST", fullURL, bytes.NewBuffer(data))
 	if err != nil {
-		if client.logger != nil {
+		if client.logEnabled(LogLevelError) {
 			client.logger.Errorf("Failed to create request for POST %s: %v", fullURL, err)
 		}
 		// Call PostRequest hooks even if NewRequestWithContext fails
@@ -217,19 +328,25 @@ func (client *Client) PostMethod(ctx context.Context, path string, body interfac
 		return fmt.Errorf("api post failed to request path: %s, err: %w", path, err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	client.setClientNameHeader(req)
+	if key, ok := IdempotencyKeyFromContext(ctx); ok {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	setRequestOptionHeaders(ctx, req)
 
-	resp, err := client.httpclient.Do(req)
+	resp, err := client.httpClientFor(ctx).Do(req)
 	if err != nil {
-		if client.logger != nil {
-			client.logger.Errorf("API POST request to %s failed: %v", fullURL, err)
+		netErr := &NetworkError{Kind: ClassifyNetworkError(err), Method: "POST", Path: path, Err: err}
+		if client.logEnabled(LogLevelError) {
+			client.logger.Errorf("API POST request to %s failed (%s): %v", fullURL, netErr.Kind, err)
 		}
 		// Call PostRequest hooks if client.httpclient.Do fails
 		if len(client.hooks) > 0 {
 			for _, hook := range client.hooks {
-				hook.PostRequest(ctx, "POST", fullURL, 0, nil, err)
+				hook.PostRequest(ctx, "POST", fullURL, 0, nil, netErr)
 			}
 		}
-		return fmt.Errorf("failed to request path: %s, err: %w", path, err)
+		return netErr
 	}
 	return client.handleAPIResponse(ctx, "POST", fullURL, resp, result)
 }
@@ -238,6 +355,17 @@ func (client *Client) PostMethod(ctx context.Context, path string, body interfac
 type ErrorResponse struct {
 	ErrorCode string `json:"error_code"`
 	Message   string `json:"message"`
+	// Details and Fields are only sent by some endpoints, mostly for
+	// validation errors.
+	Details map[string]interface{} `json:"details,omitempty"`
+	Fields  []FieldError           `json:"fields,omitempty"`
+}
+
+// FieldError describes why one field of a request was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
 }
 
 // APIError is a custom error type that includes the error response details
@@ -245,33 +373,59 @@ type APIError struct {
 	StatusCode int
 	ErrorCode  string
 	Message    string
+	Details    map[string]interface{}
+	Fields     []FieldError
 }
 
 // Error implements the error interface
 func (e *APIError) Error() string {
 	if e.ErrorCode != "" {
-		return fmt.Sprintf("API error: status=%d, code=%s, message=%s", e.StatusCode, e.ErrorCode, e.Message)
+		msg := fmt.Sprintf("API error: status=%d, code=%s, message=%s", e.StatusCode, e.ErrorCode, e.Message)
+		if len(e.Fields) > 0 {
+			fields := make([]string, len(e.Fields))
+			for i, f := range e.Fields {
+				fields[i] = f.Field + ": " + f.Message
+			}
+			msg += ", fields=[" + strings.Join(fields, "; ") + "]"
+		}
+		return msg
 	}
 	return fmt.Sprintf("API error: status=%d", e.StatusCode)
 }
 
+// FieldError returns the validation error for the named request field, if the
+// node reported one.
+func (e *APIError) FieldError(field string) (FieldError, bool) {
+	for _, f := range e.Fields {
+		if f.Field == field {
+			return f, true
+		}
+	}
+	return FieldError{}, false
+}
+
 // handleAPIResponse is a helper function to handle API responses consistently.
 // The result parameter must be a pointer to a Go value suitable for JSON unmarshalling.
 // It uses `any` because the actual type of the response varies depending on the API endpoint.
 func (client *Client) handleAPIResponse(ctx context.Context, method string, url string, resp *http.Response, result interface{}) error {
 	defer resp.Body.Close()
+	client.notifyResponseHeaders(method, url, resp)
 
 	var processingErr error
 	var bodyBytes []byte
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err := client.readResponseBody(resp.Body)
 	if err != nil {
-		if client.logger != nil {
+		if client.logEnabled(LogLevelError) {
 			client.logger.Errorf("Failed to read response body from %s %s: %v", method, url, err)
 		}
-		processingErr = &APIError{
-			StatusCode: resp.StatusCode, // Could be 0 if error happened before getting status
-			Message:    fmt.Sprintf("failed to read response body: %v", err),
+		if errors.Is(err, ErrResponseTooLarge) {
+			processingErr = err
+		} else {
+			processingErr = &APIError{
+				StatusCode: resp.StatusCode, // Could be 0 if error happened before getting status
+				Message:    fmt.Sprintf("failed to read response body: %v", err),
+			}
 		}
 		// Call PostRequest hooks before returning
 		if len(client.hooks) > 0 {
@@ -286,7 +440,7 @@ func (client *Client) handleAPIResponse(ctx context.Context, method string, url
 	if resp.StatusCode == http.StatusOK {
 		if result != nil {
 			if err := json.Unmarshal(bodyBytes, result); err != nil {
-				if client.logger != nil {
+				if client.logEnabled(LogLevelError) {
 					client.logger.Errorf("Failed to decode response from %s %s: %v. Body: %s", method, url, err, string(bodyBytes))
 				}
 				processingErr = fmt.Errorf("failed to decode response: %w. Body: %s", err, string(bodyBytes))
@@ -294,24 +448,28 @@ func (client *Client) handleAPIResponse(ctx context.Context, method string, url
 		}
 		// processingErr remains nil if decode is successful
 	} else {
-		// For non-200 responses, try to parse the error response
+		// For non-200 responses, try to parse the error response.
+		// Proxies in front of a node often answer with an HTML error page instead.
 		var errorResp ErrorResponse
 		if err := json.Unmarshal(bodyBytes, &errorResp); err != nil {
-			if client.logger != nil {
-				client.logger.Errorf("Failed to unmarshal error response from %s %s (status %d): %v. Body: %s", method, url, resp.StatusCode, err, string(bodyBytes))
+			if client.logEnabled(LogLevelError) {
+				client.logger.Errorf("Failed to unmarshal error response from %s %s (status %d): %v. Body: %s", method, url, resp.StatusCode, err, truncateBody(bodyBytes))
 			}
 			processingErr = &APIError{
 				StatusCode: resp.StatusCode,
-				Message:    fmt.Sprintf("unexpected status code: %d, body: %s", resp.StatusCode, string(bodyBytes)),
+				Message: fmt.Sprintf("unexpected status code: %d, non-JSON body (content-type %q): %s",
+					resp.StatusCode, resp.Header.Get("Content-Type"), truncateBody(bodyBytes)),
 			}
 		} else {
-			if client.logger != nil {
+			if client.logEnabled(LogLevelError) {
 				client.logger.Errorf("API Error from %s %s: status=%d, code=%s, message=%s", method, url, resp.StatusCode, errorResp.ErrorCode, errorResp.Message)
 			}
 			processingErr = &APIError{
 				StatusCode: resp.StatusCode,
 				ErrorCode:  errorResp.ErrorCode,
 				Message:    errorResp.Message,
+				Details:    errorResp.Details,
+				Fields:     errorResp.Fields,
 			}
 		}
 	}
@@ -324,3 +482,21 @@ func (client *Client) handleAPIResponse(ctx context.Context, method string, url
 	}
 	return processingErr
 }
+
+// maxErrorBodyLen bounds how much of a non-JSON error body is kept in messages.
+const maxErrorBodyLen = 200
+
+// truncateBody collapses whitespace in body and cuts it to at most
+// maxErrorBodyLen bytes, backing off to a rune boundary so the result stays
+// valid UTF-8.
+func truncateBody(body []byte) string {
+	text := strings.Join(strings.Fields(string(body)), " ")
+	if len(text) > maxErrorBodyLen {
+		cut := maxErrorBodyLen
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		return text[:cut] + "...(truncated)"
+	}
+	return text
+}
@@ -4,14 +4,17 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 // mockLogger is a comprehensive mock for the Logger interface.
@@ -815,3 +818,278 @@ func TestClientHooks(t *testing.T) {
 		}
 	})
 }
+
+// orderHook records its name into a shared log on every hook call.
+type orderHook struct {
+	name string
+	mu   *sync.Mutex
+	log  *[]string
+}
+
+func (h orderHook) PreRequest(ctx context.Context, method, url string, body []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.log = append(*h.log, "pre:"+h.name)
+}
+
+func (h orderHook) PostRequest(ctx context.Context, method, url string, statusCode int, responseBody []byte, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.log = append(*h.log, "post:"+h.name)
+}
+
+func TestClientHooks_Order(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api_error" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, `{"error_code":"BAD_INPUT","message":"Invalid input"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"output":"response"}`)
+	}))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var log []string
+	hooks := WithHooks(orderHook{"metrics", &mu, &log}, orderHook{"logging", &mu, &log})
+	want := "[pre:metrics pre:logging post:metrics post:logging]"
+
+	tests := []struct {
+		name    string
+		baseURL string
+		path    string
+		wantErr bool
+	}{
+		{"Success", server.URL, "/ok", false},
+		{"API Error", server.URL, "/api_error", true},
+		{"Network Error", "http://localhost:12345", "/some_path", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mu.Lock()
+			log = nil
+			mu.Unlock()
+			client := newClientInternal(tt.baseURL, WithTimeout(time.Second), hooks)
+			var result interface{}
+			err := client.GetMethod(context.Background(), tt.path, &result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetMethod error = %v, wantErr %v", err, tt.wantErr)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if got := fmt.Sprint(log); got != want {
+				t.Errorf("hook calls = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestClient_Ping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chains/chain_id":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"chain_id":1212101}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND", "message":"Endpoint not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	if err := client.Ping(context.Background()); err != nil {
+		t.Fatalf("Ping failed: %v", err)
+	}
+
+	emptyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{}`)
+	}))
+	defer emptyServer.Close()
+
+	client = newClientInternal(emptyServer.URL, WithTimeout(time.Second))
+	if err := client.Ping(context.Background()); err == nil {
+		t.Fatal("Expected Ping to fail for a node without a chain id, but it didn't")
+	}
+
+	client = newClientInternal("http://localhost:12345", WithTimeout(100*time.Millisecond))
+	if err := client.Ping(context.Background()); err == nil {
+		t.Fatal("Expected Ping to fail for an unreachable node, but it didn't")
+	}
+}
+
+func TestClient_WithLogLevel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/success" {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"status":"ok"}`)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintln(w, `{"error_code":"INTERNAL_ERROR","message":"Something broke on the server"}`)
+	}))
+	defer server.Close()
+
+	logger := newMockLogger(t)
+	client := newClientInternal(server.URL, WithLogger(logger), WithLogLevel(LogLevelError), WithTimeout(time.Second))
+
+	var result struct{ Status string }
+	if err := client.GetMethod(context.Background(), "/success", &result); err != nil {
+		t.Fatalf("GetMethod failed: %v", err)
+	}
+	if err := client.PostMethod(context.Background(), "/success", map[string]string{"a": "b"}, &result); err != nil {
+		t.Fatalf("PostMethod failed: %v", err)
+	}
+	if calls := logger.getInfofCalls(); len(calls) != 0 {
+		t.Errorf("Expected no Infof calls at Error level, got %d: %v", len(calls), calls)
+	}
+
+	if err := client.GetMethod(context.Background(), "/fail", &result); err == nil {
+		t.Fatal("Expected an error, got nil")
+	}
+	if calls := logger.getErrorfCalls(); len(calls) != 1 {
+		t.Errorf("Expected errors to still be logged at Error level, got %d: %v", len(calls), calls)
+	}
+}
+
+func TestClient_NonJSONErrorResponse(t *testing.T) {
+	htmlPage := "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>\n" +
+		strings.Repeat("<p>nginx upstream error</p>\n", 50) + "</body>\n</html>\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, htmlPage)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	var result interface{}
+	err := client.GetMethod(context.Background(), "/v1/chains/chain_id", &result)
+	apiErr, ok := err.(*APIError)
+	if !ok {
+		t.Fatalf("Expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway {
+		t.Errorf("Expected status %d, got %d", http.StatusBadGateway, apiErr.StatusCode)
+	}
+	if !strings.Contains(apiErr.Message, "non-JSON body") || !strings.Contains(apiErr.Message, "text/html") {
+		t.Errorf("Expected message to note the non-JSON body, got: %s", apiErr.Message)
+	}
+	if !strings.Contains(apiErr.Message, "502 Bad Gateway") {
+		t.Errorf("Expected message to keep the start of the body, got: %s", apiErr.Message)
+	}
+	if len(apiErr.Message) > 400 || strings.Contains(apiErr.Message, "\n") {
+		t.Errorf("Expected a short single-line message, got %d bytes: %q", len(apiErr.Message), apiErr.Message)
+	}
+}
+
+func TestClient_ValidationErrorDetails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error_code":"BAD_INPUT","message":"invalid payload",
+			"details":{"max_value":"1000"},
+			"fields":[{"field":"value","message":"exceeds maximum","code":"TOO_LARGE"},{"field":"recipient","message":"invalid address"}]}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	err := client.PostMethod(context.Background(), "/v1/transactions/payment", struct{}{}, nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.Details["max_value"] != "1000" {
+		t.Errorf("Unexpected details: %v", apiErr.Details)
+	}
+	field, ok := apiErr.FieldError("value")
+	if !ok || field.Message != "exceeds maximum" || field.Code != "TOO_LARGE" {
+		t.Errorf("FieldError(value) = %+v, %v", field, ok)
+	}
+	if _, ok := apiErr.FieldError("nonce"); ok {
+		t.Error("Expected no field error for nonce")
+	}
+	if !strings.Contains(err.Error(), "fields=[value: exceeds maximum; recipient: invalid address]") {
+		t.Errorf("Expected the field errors in the message, got: %v", err)
+	}
+}
+
+func TestClassifyNetworkError(t *testing.T) {
+	// Connection refused: grab a free port and close the listener so nothing answers.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	refusedURL := "http://" + ln.Addr().String()
+	ln.Close()
+
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer slow.Close()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer tlsServer.Close()
+
+	dnsClient := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, &net.DNSError{Err: "no such host", Name: "node.invalid", IsNotFound: true}
+		},
+	}}
+
+	tests := []struct {
+		name string
+		url  string
+		opts []ClientOption
+		want NetworkErrorKind
+	}{
+		{"timeout", slow.URL, []ClientOption{WithTimeout(50 * time.Millisecond)}, NetworkErrorTimeout},
+		{"connection refused", refusedURL, nil, NetworkErrorConnectionRefused},
+		{"dns failure", "http://node.invalid", []ClientOption{WithHTTPClient(dnsClient)}, NetworkErrorDNSFailure},
+		{"tls handshake", tlsServer.URL, nil, NetworkErrorTLSHandshake},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClientInternal(tt.url, tt.opts...)
+			err := client.GetMethod(context.Background(), "/v1/checkpoints/number", nil)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			var netErr *NetworkError
+			if !errors.As(err, &netErr) {
+				t.Fatalf("expected *NetworkError, got %T: %v", err, err)
+			}
+			if netErr.Kind != tt.want {
+				t.Errorf("Kind = %v, want %v (err: %v)", netErr.Kind, tt.want, err)
+			}
+			if got := ClassifyNetworkError(err); got != tt.want {
+				t.Errorf("ClassifyNetworkError = %v, want %v", got, tt.want)
+			}
+			if !IsNetworkErrorKind(err, tt.want) {
+				t.Errorf("IsNetworkErrorKind(%v) = false", tt.want)
+			}
+		})
+	}
+
+	if got := ClassifyNetworkError(errors.New("boom")); got != NetworkErrorUnknown {
+		t.Errorf("ClassifyNetworkError(plain error) = %v, want unknown", got)
+	}
+	if IsNetworkErrorKind(&APIError{StatusCode: 500}, NetworkErrorUnknown) {
+		t.Error("APIError must not be reported as a network error")
+	}
+}
+
+func TestTruncateBody_RuneBoundary(t *testing.T) {
+	// 199 ASCII bytes followed by a 3-byte rune straddling the 200-byte cut.
+	body := []byte(strings.Repeat("a", maxErrorBodyLen-1) + "€tail")
+	got := truncateBody(body)
+	if !utf8.ValidString(got) {
+		t.Fatalf("truncateBody produced invalid UTF-8: %q", got)
+	}
+	if want := strings.Repeat("a", maxErrorBodyLen-1) + "...(truncated)"; got != want {
+		t.Errorf("truncateBody = %q, want %q", got, want)
+	}
+}
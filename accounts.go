@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type TokenAccountResponse struct {
@@ -12,6 +15,12 @@ type TokenAccountResponse struct {
 	TokenAccountAddress string `json:"token_account_address"`
 }
 
+// TokenBalance is an address's balance of one token.
+type TokenBalance struct {
+	Token   string `json:"token"`
+	Balance string `json:"balance"`
+}
+
 type AccountNonceResponse struct {
 	Nonce uint64 `json:"nonce"`
 }
@@ -30,3 +39,143 @@ func (client *Client) GetAccountNonce(ctx context.Context, address string) (*Acc
 	params.Set("address", address)
 	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/accounts/nonce?%s", params.Encode()), result)
 }
+
+// AccountActivity is the node's count of an account's transactions.
+type AccountActivity struct {
+	Address          string `json:"address"`
+	TransactionCount uint64 `json:"transaction_count"`
+	LastNonce        uint64 `json:"last_nonce"`
+	// TotalVolume is the summed value the account has sent, as a decimal
+	// string. Nodes that do not track volume leave it empty.
+	TotalVolume string `json:"total_volume,omitempty"`
+}
+
+// GetAccountActivity returns how many transactions address has sent and its
+// last used nonce, for cross-checking client-side counters.
+func (client *Client) GetAccountActivity(ctx context.Context, address string) (*AccountActivity, error) {
+	result := new(AccountActivity)
+	params := url.Values{}
+	params.Set("address", address)
+	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/accounts/activity?%s", params.Encode()), result)
+}
+
+// GetAccountBalances returns the address's balance of every token it holds, in
+// one call.
+func (client *Client) GetAccountBalances(ctx context.Context, address string) ([]TokenBalance, error) {
+	var result []TokenBalance
+	params := url.Values{}
+	params.Set("address", address)
+	if err := client.GetMethod(ctx, fmt.Sprintf("/v1/accounts/balances?%s", params.Encode()), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetDerivedTokenAccount asks the node for the token account address of wallet
+// for the given mint. It should always equal DeriveTokenAccountAddress; use it to
+// confirm the local derivation matches the node's.
+func (client *Client) GetDerivedTokenAccount(ctx context.Context, wallet, mint common.Address) (common.Address, error) {
+	result, err := client.GetTokenAccount(ctx, wallet.Hex(), mint.Hex())
+	if err != nil {
+		return common.Address{}, err
+	}
+	if !common.IsHexAddress(result.TokenAccountAddress) {
+		return common.Address{}, fmt.Errorf("node returned invalid token account address %q", result.TokenAccountAddress)
+	}
+	return common.HexToAddress(result.TokenAccountAddress), nil
+}
+
+// MaxAccountListPageSize is the largest page GetAccountList asks for.
+const MaxAccountListPageSize = 1000
+
+// AccountSummary is one account in GetAccountList.
+type AccountSummary struct {
+	Address           string `json:"address"`
+	Nonce             uint64 `json:"nonce"`
+	TokenAccountCount int    `json:"token_account_count"`
+}
+
+// AccountListPage is one page of GetAccountList. NextCursor is empty on the
+// last page. Total is the number of accounts on the network.
+type AccountListPage struct {
+	Accounts   []AccountSummary `json:"accounts"`
+	NextCursor string           `json:"next_cursor"`
+	Total      int64            `json:"total"`
+}
+
+// GetAccountList returns one page of all accounts on the network, starting at
+// cursor ("" for the first page). pageSize is capped at MaxAccountListPageSize;
+// zero lets the node choose. The full list can be very large, so prefer
+// AccountListIterator over collecting it in memory.
+func (client *Client) GetAccountList(ctx context.Context, pageSize int, cursor string) (*AccountListPage, error) {
+	result := new(AccountListPage)
+	params := url.Values{}
+	if pageSize > MaxAccountListPageSize {
+		pageSize = MaxAccountListPageSize
+	}
+	if pageSize > 0 {
+		params.Set("limit", strconv.Itoa(pageSize))
+	}
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/accounts/list?%s", params.Encode()), result)
+}
+
+// AccountListIterator walks every page of GetAccountList. Call Next until it
+// returns false, reading each account with Account, then check Err.
+type AccountListIterator struct {
+	client   *Client
+	pageSize int
+	cursor   string
+	total    int64
+
+	page []AccountSummary
+	pos  int
+	done bool
+	err  error
+}
+
+// NewAccountListIterator returns an iterator over all accounts, fetching
+// pageSize accounts per request.
+func NewAccountListIterator(client *Client, pageSize int) *AccountListIterator {
+	return &AccountListIterator{client: client, pageSize: pageSize, pos: -1}
+}
+
+// Next advances to the next account, fetching the next page when needed. It
+// returns false at the end of the list or on error; check Err.
+func (it *AccountListIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	it.pos++
+	for it.pos >= len(it.page) {
+		if it.done {
+			return false
+		}
+		page, err := it.client.GetAccountList(ctx, it.pageSize, it.cursor)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.pos = page.Accounts, 0
+		it.cursor, it.total = page.NextCursor, page.Total
+		it.done = page.NextCursor == ""
+	}
+	return true
+}
+
+// Account returns the current account. It is only valid after Next returned true.
+func (it *AccountListIterator) Account() AccountSummary {
+	return it.page[it.pos]
+}
+
+// Total returns the account total reported with the last fetched page.
+func (it *AccountListIterator) Total() int64 {
+	return it.total
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *AccountListIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,190 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetAccountBalances(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000001"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/accounts/balances" || r.URL.Query().Get("address") != address {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND", "message":"Endpoint not found"}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `[{"token":"0x00000000000000000000000000000000000000aa","balance":"1500"},
+			{"token":"0x00000000000000000000000000000000000000bb","balance":"0"}]`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	balances, err := client.GetAccountBalances(context.Background(), address)
+	if err != nil {
+		t.Fatalf("GetAccountBalances failed: %v", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("Expected 2 balances, got %d", len(balances))
+	}
+	if balances[0].Token != "0x00000000000000000000000000000000000000aa" || balances[0].Balance != "1500" {
+		t.Errorf("Unexpected first balance: %+v", balances[0])
+	}
+	if balances[1].Balance != "0" {
+		t.Errorf("Unexpected second balance: %+v", balances[1])
+	}
+}
+
+func TestGetAccountActivity(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000001"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/accounts/activity" || r.URL.Query().Get("address") != address {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND", "message":"Endpoint not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"address":%q,"transaction_count":12,"last_nonce":11,"total_volume":"5000"}`, address)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	activity, err := client.GetAccountActivity(context.Background(), address)
+	if err != nil {
+		t.Fatalf("GetAccountActivity failed: %v", err)
+	}
+	want := AccountActivity{Address: address, TransactionCount: 12, LastNonce: 11, TotalVolume: "5000"}
+	if *activity != want {
+		t.Errorf("GetAccountActivity = %+v, want %+v", *activity, want)
+	}
+}
+
+func TestAccountListIterator(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/accounts/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		queries = append(queries, r.URL.RawQuery)
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprintln(w, `{"accounts":[
+				{"address":"0x01","nonce":3,"token_account_count":2},
+				{"address":"0x02","nonce":0,"token_account_count":1}
+			],"next_cursor":"c2","total":3}`)
+		case "c2":
+			fmt.Fprintln(w, `{"accounts":[{"address":"0x03","nonce":7,"token_account_count":4}],"next_cursor":"","total":3}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	page, err := client.GetAccountList(context.Background(), 5000, "")
+	if err != nil {
+		t.Fatalf("GetAccountList failed: %v", err)
+	}
+	if len(page.Accounts) != 2 || page.NextCursor != "c2" || page.Total != 3 {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	if page.Accounts[0] != (AccountSummary{Address: "0x01", Nonce: 3, TokenAccountCount: 2}) {
+		t.Errorf("Unexpected account: %+v", page.Accounts[0])
+	}
+	if queries[0] != "limit=1000" {
+		t.Errorf("Expected the page size to be capped, got query %q", queries[0])
+	}
+
+	queries = nil
+	it := NewAccountListIterator(client, 2)
+	var addresses []string
+	for it.Next(context.Background()) {
+		addresses = append(addresses, it.Account().Address)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iteration failed: %v", err)
+	}
+	if fmt.Sprint(addresses) != "[0x01 0x02 0x03]" || it.Total() != 3 {
+		t.Errorf("Iterated %v (total %d)", addresses, it.Total())
+	}
+	if fmt.Sprint(queries) != "[limit=2 cursor=c2&limit=2]" {
+		t.Errorf("Unexpected page requests: %v", queries)
+	}
+}
+
+func TestGetDerivedTokenAccount(t *testing.T) {
+	tests := []struct {
+		name   string
+		wallet common.Address
+		mint   common.Address
+		want   common.Address
+	}{
+		{
+			name:   "small addresses",
+			wallet: common.HexToAddress("0x0000000000000000000000000000000000000001"),
+			mint:   common.HexToAddress("0x0000000000000000000000000000000000000002"),
+			want:   common.HexToAddress("0xbF60712984058251881a79A749dfF0c99C6c4B5f"),
+		},
+		{
+			name:   "zero addresses",
+			wallet: common.Address{},
+			mint:   common.Address{},
+			want:   common.HexToAddress("0x841a6556c524D47030762eb14dC4Af897e605d9b"),
+		},
+		{
+			name:   "mixed-case addresses",
+			wallet: common.HexToAddress("0xA634dfba8c7550550817898bC4820cD10888Aac5"),
+			mint:   common.HexToAddress("0x8E9d1b45293e30EF38564582979195DD16A16E13"),
+			want:   common.HexToAddress("0x91B6191015e41469Ba2feBd7e1722A8eE83DE15B"),
+		},
+		{
+			// Wallet and mint are not interchangeable.
+			name:   "swapped wallet and mint",
+			wallet: common.HexToAddress("0x8E9d1b45293e30EF38564582979195DD16A16E13"),
+			mint:   common.HexToAddress("0xA634dfba8c7550550817898bC4820cD10888Aac5"),
+			want:   common.HexToAddress("0x45fEeB82a7e36662B43E1e5C8acCcE752b6EED87"),
+		},
+	}
+
+	accounts := make(map[[2]string]string)
+	for _, tt := range tests {
+		accounts[[2]string{tt.wallet.Hex(), tt.mint.Hex()}] = tt.want.Hex()
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenAccount, ok := accounts[[2]string{r.URL.Query().Get("address"), r.URL.Query().Get("token")}]
+		if r.URL.Path != "/v1/accounts/token_account" || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"balance":"0","nonce":0,"token_account_address":%q}`, tokenAccount)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if local := client.DeriveTokenAccountAddress(tt.wallet, tt.mint); local != tt.want {
+				t.Errorf("DeriveTokenAccountAddress = %s, want %s", local.Hex(), tt.want.Hex())
+			}
+			derived, err := client.GetDerivedTokenAccount(context.Background(), tt.wallet, tt.mint)
+			if err != nil {
+				t.Fatalf("GetDerivedTokenAccount failed: %v", err)
+			}
+			if derived != tt.want {
+				t.Errorf("GetDerivedTokenAccount = %s, want %s", derived.Hex(), tt.want.Hex())
+			}
+		})
+	}
+
+	invalid := tests[0]
+	accounts[[2]string{invalid.wallet.Hex(), invalid.mint.Hex()}] = "not-an-address"
+	if _, err := client.GetDerivedTokenAccount(context.Background(), invalid.wallet, invalid.mint); err == nil {
+		t.Error("Expected an error for an invalid token account address")
+	}
+}
@@ -0,0 +1,165 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AmountValidationMode controls how MintToken and SendPayment react to amounts
+// that look wrongly scaled for the token's decimals.
+type AmountValidationMode int
+
+const (
+	// AmountValidationOff disables amount validation (the default).
+	AmountValidationOff AmountValidationMode = iota
+	// AmountValidationWarn logs a warning through the client's logger and submits anyway.
+	AmountValidationWarn
+	// AmountValidationError rejects the request with ErrSuspiciousAmount before submitting.
+	AmountValidationError
+)
+
+// tokenInfoCacheTTL bounds how stale the cached supply used for amount validation may be.
+const tokenInfoCacheTTL = time.Minute
+
+// ErrSuspiciousAmount is returned when amount validation is in AmountValidationError
+// mode and a value is more than 10^decimals times the token's supply.
+var ErrSuspiciousAmount = errors.New("amount looks wrongly scaled for token decimals")
+
+type cachedTokenInfo struct {
+	decimals  uint8
+	supply    *big.Int
+	fetchedAt time.Time
+}
+
+type tokenInfoCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedTokenInfo
+}
+
+// WithAmountValidation enables checking MintToken and SendPayment values against
+// the token's decimals and current supply. Token metadata is fetched on first
+// use and cached for a minute.
+func WithAmountValidation(mode AmountValidationMode) ClientOption {
+	return func(c *Client) {
+		c.amountValidation = mode
+	}
+}
+
+// tokenInfo returns the cached decimals and supply for token, fetching them when
+// missing or older than tokenInfoCacheTTL.
+func (client *Client) tokenInfo(ctx context.Context, token string) (*cachedTokenInfo, error) {
+	key := strings.ToLower(token)
+	client.tokenInfos.mu.Lock()
+	entry, ok := client.tokenInfos.entries[key]
+	client.tokenInfos.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < tokenInfoCacheTTL {
+		return entry, nil
+	}
+
+	meta, err := client.GetTokenMetadata(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	supply, ok := new(big.Int).SetString(meta.Supply, 10)
+	if !ok {
+		supply = new(big.Int)
+	}
+	entry = &cachedTokenInfo{decimals: meta.Decimals, supply: supply, fetchedAt: time.Now()}
+
+	client.tokenInfos.mu.Lock()
+	if client.tokenInfos.entries == nil {
+		client.tokenInfos.entries = make(map[string]*cachedTokenInfo)
+	}
+	client.tokenInfos.entries[key] = entry
+	client.tokenInfos.mu.Unlock()
+	return entry, nil
+}
+
+// DecimalsFor returns the token's decimals. They cannot change once a token is
+// issued, so after the first GetTokenMetadata call they are served from the
+// client's cache for good.
+func (client *Client) DecimalsFor(ctx context.Context, token string) (uint8, error) {
+	client.tokenInfos.mu.Lock()
+	entry, ok := client.tokenInfos.entries[strings.ToLower(token)]
+	client.tokenInfos.mu.Unlock()
+	if ok {
+		return entry.decimals, nil
+	}
+	info, err := client.tokenInfo(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return info.decimals, nil
+}
+
+// FormatTokenAmount renders value, in the token's smallest unit, as a decimal
+// number of whole tokens without trailing zeros, e.g. 1500000 with 6 decimals
+// is "1.5". A nil value is "0".
+func FormatTokenAmount(value *big.Int, decimals uint8) string {
+	if value == nil {
+		return "0"
+	}
+	digits := new(big.Int).Abs(value).String()
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals == 0 {
+		return sign + digits
+	}
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	point := len(digits) - int(decimals)
+	fraction := strings.TrimRight(digits[point:], "0")
+	if fraction == "" {
+		return sign + digits[:point]
+	}
+	return sign + digits[:point] + "." + fraction
+}
+
+// validateAmount applies the client's AmountValidationMode to value for token.
+// A value is suspicious when it is more than 10^decimals times the token's
+// current supply, the signature of scaling by decimals twice. Small values are
+// not checked: a value that was never scaled is indistinguishable from an
+// ordinary fractional payment.
+func (client *Client) validateAmount(ctx context.Context, token common.Address, value *big.Int) error {
+	if client.amountValidation == AmountValidationOff || value == nil {
+		return nil
+	}
+	info, err := client.tokenInfo(ctx, token.Hex())
+	if err != nil {
+		if client.amountValidation == AmountValidationError {
+			return fmt.Errorf("fetch token metadata for amount validation: %w", err)
+		}
+		if client.logEnabled(LogLevelWarn) {
+			client.logger.Warnf("Skipping amount validation for token %s: %v", token.Hex(), err)
+		}
+		return nil
+	}
+	if info.supply.Sign() == 0 || value.Sign() == 0 {
+		return nil
+	}
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(info.decimals)), nil)
+	if value.Cmp(new(big.Int).Mul(info.supply, unit)) <= 0 {
+		return nil
+	}
+
+	if client.amountValidation == AmountValidationError {
+		return fmt.Errorf("%w: value %s is more than 10^decimals times the current supply (supply %s, decimals %d)",
+			ErrSuspiciousAmount, value, info.supply, info.decimals)
+	}
+	if client.logEnabled(LogLevelWarn) {
+		client.logger.Warnf("Value %s for token %s is more than 10^decimals times the current supply (supply %s, decimals %d); check the amount is scaled correctly",
+			value, token.Hex(), info.supply, info.decimals)
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newAmountValidationServer(metadataCalls, paymentCalls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/tokens/token_metadata":
+			atomic.AddInt32(metadataCalls, 1)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"symbol":"USDA","decimals":6,"supply":"1000000000000"}`)
+		case "/v1/transactions/payment":
+			atomic.AddInt32(paymentCalls, 1)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"hash":"0xabc"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestWithAmountValidation_Error(t *testing.T) {
+	var metadataCalls, paymentCalls int32
+	server := newAmountValidationServer(&metadataCalls, &paymentCalls)
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithAmountValidation(AmountValidationError))
+	req := &PaymentRequest{PaymentPayload: PaymentPayload{
+		Token: common.HexToAddress("0x0000000000000000000000000000000000000003"),
+		Value: NewTokenValue(big.NewInt(5_000_000)),
+	}}
+	if _, err := client.SendPayment(context.Background(), req); err != nil {
+		t.Fatalf("Expected a correctly scaled payment to pass, got: %v", err)
+	}
+
+	tooLarge, _ := new(big.Int).SetString("10000000000000000000", 10)
+	req.Value = NewTokenValue(tooLarge)
+	_, err := client.SendPayment(context.Background(), req)
+	if !errors.Is(err, ErrSuspiciousAmount) {
+		t.Fatalf("Expected ErrSuspiciousAmount for an oversized payment, got: %v", err)
+	}
+
+	// 0.50 of a 6-decimal token is an ordinary payment, not a scaling mistake.
+	req.Value = NewTokenValue(big.NewInt(500_000))
+	if _, err := client.SendPayment(context.Background(), req); err != nil {
+		t.Fatalf("Expected a fractional payment to pass, got: %v", err)
+	}
+
+	if paymentCalls != 2 {
+		t.Errorf("Expected 2 payments to reach the server, got %d", paymentCalls)
+	}
+	if metadataCalls != 1 {
+		t.Errorf("Expected token metadata to be fetched once and cached, got %d fetches", metadataCalls)
+	}
+}
+
+func TestWithAmountValidation_LargeSupply(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/tokens/token_metadata":
+			// 10^12 whole tokens with 6 decimals.
+			fmt.Fprintln(w, `{"symbol":"BIG","decimals":6,"supply":"1000000000000000000"}`)
+		case "/v1/transactions/payment":
+			fmt.Fprintln(w, `{"hash":"0xabc"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithAmountValidation(AmountValidationError))
+	req := &PaymentRequest{PaymentPayload: PaymentPayload{
+		Token: common.HexToAddress("0x0000000000000000000000000000000000000003"),
+		Value: NewTokenValue(big.NewInt(1_000_000)),
+	}}
+	if _, err := client.SendPayment(context.Background(), req); err != nil {
+		t.Errorf("Expected a payment of one whole token to pass, got: %v", err)
+	}
+}
+
+func TestWithAmountValidation_Warn(t *testing.T) {
+	var metadataCalls, paymentCalls int32
+	server := newAmountValidationServer(&metadataCalls, &paymentCalls)
+	defer server.Close()
+
+	logger := newMockLogger(t)
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithLogger(logger), WithAmountValidation(AmountValidationWarn))
+	tooLarge, _ := new(big.Int).SetString("10000000000000000000", 10)
+	req := &PaymentRequest{PaymentPayload: PaymentPayload{
+		Token: common.HexToAddress("0x0000000000000000000000000000000000000003"),
+		Value: NewTokenValue(tooLarge),
+	}}
+	if _, err := client.SendPayment(context.Background(), req); err != nil {
+		t.Fatalf("Expected warn mode to submit anyway, got: %v", err)
+	}
+	if paymentCalls != 1 {
+		t.Errorf("Expected the payment to reach the server, got %d calls", paymentCalls)
+	}
+	logger.mu.Lock()
+	warnings := len(logger.warnfCalls)
+	logger.mu.Unlock()
+	if warnings != 1 {
+		t.Errorf("Expected 1 Warnf call, got %d", warnings)
+	}
+}
+
+func TestDecimalsFor(t *testing.T) {
+	var metadataCalls, paymentCalls int32
+	server := newAmountValidationServer(&metadataCalls, &paymentCalls)
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	token := "0x00000000000000000000000000000000000000AB"
+	for i := 0; i < 3; i++ {
+		decimals, err := client.DecimalsFor(context.Background(), token)
+		if err != nil {
+			t.Fatalf("DecimalsFor failed: %v", err)
+		}
+		if decimals != 6 {
+			t.Errorf("DecimalsFor = %d, want 6", decimals)
+		}
+	}
+	// Decimals never change, so a stale cache entry is still used.
+	client.tokenInfos.entries[strings.ToLower(token)].fetchedAt = time.Time{}
+	if _, err := client.DecimalsFor(context.Background(), strings.ToLower(token)); err != nil {
+		t.Fatalf("DecimalsFor failed: %v", err)
+	}
+	if metadataCalls != 1 {
+		t.Errorf("Expected 1 metadata call, got %d", metadataCalls)
+	}
+}
+
+func TestFormatTokenAmount(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	tests := []struct {
+		value    *big.Int
+		decimals uint8
+		want     string
+	}{
+		{big.NewInt(1500000), 6, "1.5"},
+		{big.NewInt(1000000), 6, "1"},
+		{big.NewInt(1), 6, "0.000001"},
+		{big.NewInt(0), 6, "0"},
+		{big.NewInt(-2500), 3, "-2.5"},
+		{big.NewInt(42), 0, "42"},
+		{huge, 18, "123456789012.34567890123456789"},
+		{nil, 6, "0"},
+	}
+	for _, tt := range tests {
+		if got := FormatTokenAmount(tt.value, tt.decimals); got != tt.want {
+			t.Errorf("FormatTokenAmount(%v, %d) = %q, want %q", tt.value, tt.decimals, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+)
+
+// TokenHolder is one entry of TokenAnalytics.TopHolders.
+type TokenHolder struct {
+	Address string `json:"address"`
+	Balance string `json:"balance"`
+}
+
+// TokenAnalytics is the node's summary of a token's supply and activity.
+// Amounts are decimal strings in the token's smallest unit.
+type TokenAnalytics struct {
+	TotalSupply       string        `json:"total_supply"`
+	CirculatingSupply string        `json:"circulating_supply"`
+	TotalMinted       string        `json:"total_minted"`
+	TotalBurned       string        `json:"total_burned"`
+	HolderCount       int           `json:"holder_count"`
+	DailyVolume       string        `json:"daily_volume"`
+	TopHolders        []TokenHolder `json:"top_holders"`
+	// MintVelocity is the recent mint rate in tokens per hour.
+	MintVelocity float64 `json:"mint_velocity"`
+}
+
+// GetTokenAnalytics returns supply, holder and volume statistics for the token.
+func (client *Client) GetTokenAnalytics(ctx context.Context, tokenAddress string) (*TokenAnalytics, error) {
+	result := new(TokenAnalytics)
+	params := url.Values{}
+	params.Set("token", tokenAddress)
+	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/tokens/analytics?%s", params.Encode()), result)
+}
+
+// AnalyticsDiff is the change from one TokenAnalytics snapshot to a later one.
+type AnalyticsDiff struct {
+	TotalSupplyDelta       *big.Int
+	CirculatingSupplyDelta *big.Int
+	MintedDelta            *big.Int
+	BurnedDelta            *big.Int
+	DailyVolumeDelta       *big.Int
+	HolderCountDelta       int
+	MintVelocityDelta      float64
+	// NewTopHolders are in b's top holders but not a's; DroppedTopHolders the reverse.
+	NewTopHolders     []string
+	DroppedTopHolders []string
+}
+
+// CompareAnalytics returns b minus a. An empty amount counts as zero; any other
+// amount that is not a base-10 integer is an error naming the field, so a
+// malformed snapshot never shows up as a bogus delta.
+func CompareAnalytics(a, b *TokenAnalytics) (*AnalyticsDiff, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("compare analytics: nil snapshot")
+	}
+	var err error
+	delta := func(field, before, after string) *big.Int {
+		if err != nil {
+			return nil
+		}
+		var x, y *big.Int
+		if x, err = parseAmount(field, before); err != nil {
+			return nil
+		}
+		if y, err = parseAmount(field, after); err != nil {
+			return nil
+		}
+		return y.Sub(y, x)
+	}
+	diff := &AnalyticsDiff{
+		TotalSupplyDelta:       delta("total_supply", a.TotalSupply, b.TotalSupply),
+		CirculatingSupplyDelta: delta("circulating_supply", a.CirculatingSupply, b.CirculatingSupply),
+		MintedDelta:            delta("total_minted", a.TotalMinted, b.TotalMinted),
+		BurnedDelta:            delta("total_burned", a.TotalBurned, b.TotalBurned),
+		DailyVolumeDelta:       delta("daily_volume", a.DailyVolume, b.DailyVolume),
+		HolderCountDelta:       b.HolderCount - a.HolderCount,
+		MintVelocityDelta:      b.MintVelocity - a.MintVelocity,
+	}
+	if err != nil {
+		return nil, fmt.Errorf("compare analytics: %w", err)
+	}
+	diff.NewTopHolders = holdersMissingFrom(b.TopHolders, a.TopHolders)
+	diff.DroppedTopHolders = holdersMissingFrom(a.TopHolders, b.TopHolders)
+	return diff, nil
+}
+
+func parseAmount(field, s string) (*big.Int, error) {
+	if s == "" {
+		return new(big.Int), nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid %s %q", field, s)
+	}
+	return n, nil
+}
+
+// holdersMissingFrom returns the addresses in list that are not in other.
+func holdersMissingFrom(list, other []TokenHolder) []string {
+	addresses := make([]string, len(other))
+	for i, h := range other {
+		addresses[i] = h.Address
+	}
+	var missing []string
+	for _, h := range list {
+		if !containsAddress(addresses, h.Address) {
+			missing = append(missing, h.Address)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,99 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleAnalytics = `{
+	"total_supply": "1000000",
+	"circulating_supply": "900000",
+	"total_minted": "1200000",
+	"total_burned": "200000",
+	"holder_count": 42,
+	"daily_volume": "35000",
+	"top_holders": [
+		{"address": "0x0000000000000000000000000000000000000001", "balance": "500000"},
+		{"address": "0x0000000000000000000000000000000000000002", "balance": "250000"}
+	],
+	"mint_velocity": 12.5
+}`
+
+func TestGetTokenAnalytics(t *testing.T) {
+	token := "0x0000000000000000000000000000000000000009"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/analytics" || r.URL.Query().Get("token") != token {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND", "message":"Endpoint not found"}`)
+			return
+		}
+		fmt.Fprintln(w, sampleAnalytics)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	a, err := client.GetTokenAnalytics(context.Background(), token)
+	if err != nil {
+		t.Fatalf("GetTokenAnalytics failed: %v", err)
+	}
+	if a.TotalSupply != "1000000" || a.CirculatingSupply != "900000" || a.TotalMinted != "1200000" ||
+		a.TotalBurned != "200000" || a.HolderCount != 42 || a.DailyVolume != "35000" || a.MintVelocity != 12.5 {
+		t.Errorf("Unexpected analytics: %+v", a)
+	}
+	if len(a.TopHolders) != 2 || a.TopHolders[1].Address != "0x0000000000000000000000000000000000000002" || a.TopHolders[1].Balance != "250000" {
+		t.Errorf("Unexpected top holders: %+v", a.TopHolders)
+	}
+}
+
+func TestCompareAnalytics(t *testing.T) {
+	a := &TokenAnalytics{
+		TotalSupply: "1000", TotalMinted: "1200", TotalBurned: "200", HolderCount: 10, MintVelocity: 2,
+		TopHolders: []TokenHolder{{Address: "0x0000000000000000000000000000000000000001"}, {Address: "0x0000000000000000000000000000000000000002"}},
+	}
+	b := &TokenAnalytics{
+		TotalSupply: "900", TotalMinted: "1200", TotalBurned: "300", HolderCount: 12, MintVelocity: 0.5, DailyVolume: "50",
+		TopHolders: []TokenHolder{{Address: "0x0000000000000000000000000000000000000001"}, {Address: "0x0000000000000000000000000000000000000003"}},
+	}
+	diff, err := CompareAnalytics(a, b)
+	if err != nil {
+		t.Fatalf("CompareAnalytics failed: %v", err)
+	}
+	if diff.TotalSupplyDelta.Int64() != -100 || diff.MintedDelta.Sign() != 0 || diff.BurnedDelta.Int64() != 100 || diff.DailyVolumeDelta.Int64() != 50 {
+		t.Errorf("Unexpected amount deltas: %+v", diff)
+	}
+	if diff.HolderCountDelta != 2 || diff.MintVelocityDelta != -1.5 {
+		t.Errorf("HolderCountDelta = %d, MintVelocityDelta = %v", diff.HolderCountDelta, diff.MintVelocityDelta)
+	}
+	if len(diff.NewTopHolders) != 1 || diff.NewTopHolders[0] != "0x0000000000000000000000000000000000000003" {
+		t.Errorf("NewTopHolders = %v", diff.NewTopHolders)
+	}
+	if len(diff.DroppedTopHolders) != 1 || diff.DroppedTopHolders[0] != "0x0000000000000000000000000000000000000002" {
+		t.Errorf("DroppedTopHolders = %v", diff.DroppedTopHolders)
+	}
+}
+
+func TestCompareAnalytics_Invalid(t *testing.T) {
+	valid := &TokenAnalytics{TotalSupply: "1000", TotalBurned: "200"}
+	if _, err := CompareAnalytics(nil, valid); err == nil {
+		t.Error("Expected an error for a nil snapshot")
+	}
+	if _, err := CompareAnalytics(valid, nil); err == nil {
+		t.Error("Expected an error for a nil snapshot")
+	}
+
+	malformed := &TokenAnalytics{TotalSupply: "1000", TotalBurned: "2e2"}
+	for _, pair := range [][2]*TokenAnalytics{{valid, malformed}, {malformed, valid}} {
+		diff, err := CompareAnalytics(pair[0], pair[1])
+		if err == nil {
+			t.Fatalf("Expected an error for an unparseable amount, got %+v", diff)
+		}
+		if !strings.Contains(err.Error(), "total_burned") {
+			t.Errorf("Error should name the malformed field: %v", err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package onemoney
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrRequestTooLarge is returned by PostMethod, before anything is sent, when
+// the marshalled body exceeds the WithMaxRequestBodySize limit.
+var ErrRequestTooLarge = errors.New("request body too large")
+
+// ErrResponseTooLarge is returned when a response body exceeds the
+// WithMaxResponseBodySize limit.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// WithMaxRequestBodySize rejects POST bodies larger than maxBytes with
+// ErrRequestTooLarge. Zero, the default, means no limit.
+func WithMaxRequestBodySize(maxBytes int64) ClientOption {
+	return func(c *Client) {
+		c.maxRequestBodySize = maxBytes
+	}
+}
+
+// WithMaxResponseBodySize stops reading a response after maxBytes and fails
+// the request with ErrResponseTooLarge, so a misbehaving node cannot make the
+// client buffer an unbounded body. Zero, the default, means no limit.
+func WithMaxResponseBodySize(maxBytes int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseBodySize = maxBytes
+	}
+}
+
+// checkRequestSize applies the WithMaxRequestBodySize limit to a marshalled body.
+func (client *Client) checkRequestSize(data []byte) error {
+	if client.maxRequestBodySize > 0 && int64(len(data)) > client.maxRequestBodySize {
+		return fmt.Errorf("%w: %d bytes, limit %d", ErrRequestTooLarge, len(data), client.maxRequestBodySize)
+	}
+	return nil
+}
+
+// readResponseBody reads body up to the WithMaxResponseBodySize limit.
+func (client *Client) readResponseBody(body io.Reader) ([]byte, error) {
+	if client.maxResponseBodySize <= 0 {
+		return io.ReadAll(body)
+	}
+	data, err := io.ReadAll(io.LimitReader(body, client.maxResponseBodySize+1))
+	if err != nil {
+		return data, err
+	}
+	if int64(len(data)) > client.maxResponseBodySize {
+		return nil, fmt.Errorf("%w: limit %d bytes", ErrResponseTooLarge, client.maxResponseBodySize)
+	}
+	return data, nil
+}
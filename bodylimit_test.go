@@ -0,0 +1,56 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxRequestBodySize(t *testing.T) {
+	var posts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts.Add(1)
+		fmt.Fprintln(w, `{"hash":"0xabc"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithMaxRequestBodySize(64))
+	var result PaymentResponse
+	if err := client.PostMethod(context.Background(), "/v1/transactions/payment", map[string]string{"a": "b"}, &result); err != nil {
+		t.Fatalf("Small body rejected: %v", err)
+	}
+	err := client.PostMethod(context.Background(), "/v1/transactions/payment", map[string]string{"a": strings.Repeat("x", 100)}, &result)
+	if !errors.Is(err, ErrRequestTooLarge) {
+		t.Fatalf("Expected ErrRequestTooLarge, got %v", err)
+	}
+	if posts.Load() != 1 {
+		t.Errorf("Oversized body should not be sent, server saw %d requests", posts.Load())
+	}
+}
+
+func TestWithMaxResponseBodySize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("big") != "" {
+			fmt.Fprintf(w, `{"chain_id":1,"padding":%q}`, strings.Repeat("x", 1000))
+			return
+		}
+		fmt.Fprint(w, `{"chain_id":1}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithMaxResponseBodySize(100))
+	var result ChainIdResponse
+	if err := client.GetMethod(context.Background(), "/v1/chains/chain_id", &result); err != nil || result.ChainId != 1 {
+		t.Fatalf("Small response rejected: %+v, %v", result, err)
+	}
+	err := client.GetMethod(context.Background(), "/v1/chains/chain_id?big=1", &result)
+	if !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("Expected ErrResponseTooLarge, got %v", err)
+	}
+}
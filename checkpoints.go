@@ -2,11 +2,18 @@ package onemoney
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/url"
 	"strconv"
+	"sync"
+	"time"
 )
 
+// ErrCheckpointTooStale is returned before submission when a payload's
+// RecentCheckpoint is older than the limit set with WithMaxCheckpointAge.
+var ErrCheckpointTooStale = errors.New("checkpoint too stale, refresh before signing")
+
 type CheckpointNumber struct {
 	Number int `json:"number"`
 }
@@ -43,9 +50,172 @@ type CheckpointDetail struct {
 	Size             int      `json:"size"`
 }
 
+// WithMaxCheckpointAge makes signed submissions fail fast with ErrCheckpointTooStale
+// when their RecentCheckpoint is more than maxAge checkpoints behind the latest
+// checkpoint this client has seen through GetCheckpointNumber.
+func WithMaxCheckpointAge(maxAge uint64) ClientOption {
+	return func(c *Client) {
+		c.maxCheckpointAge = maxAge
+	}
+}
+
+// WithCheckpointCache makes GetCheckpointNumber reuse the last fetched number for
+// ttl. Concurrent callers share a single fetch. Use ForceRefreshCheckpoint to
+// bypass the cache.
+func WithCheckpointCache(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.checkpointCache.ttl = ttl
+	}
+}
+
+// WithCheckpointOffset makes RecentCheckpoint, and the helpers that build
+// payloads with it, use offset checkpoints before the latest. Some nodes reject
+// a RecentCheckpoint equal to the latest, not yet finalized, checkpoint.
+func WithCheckpointOffset(offset uint64) ClientOption {
+	return func(c *Client) {
+		c.checkpointOffset = offset
+	}
+}
+
+type checkpointCache struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	number    int
+	fetchedAt time.Time
+	fetch     *sharedFetch[int] // in-flight refresh, if any
+}
+
+// sharedFetch is a fetch that concurrent cache misses wait on together. It
+// runs without the cache lock held and detached from the starting caller's
+// cancellation, so every waiter is bounded by its own ctx only.
+type sharedFetch[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// startSharedFetch runs fetch in the background and calls store with its
+// result before releasing waiters. ctx supplies values, not cancellation.
+func startSharedFetch[T any](ctx context.Context, fetch func(context.Context) (T, error), store func(T, error)) *sharedFetch[T] {
+	f := &sharedFetch[T]{done: make(chan struct{})}
+	go func() {
+		defer close(f.done)
+		f.value, f.err = fetch(context.WithoutCancel(ctx))
+		store(f.value, f.err)
+	}()
+	return f
+}
+
+// wait returns the fetch result, or ctx.Err() if ctx is done first.
+func (f *sharedFetch[T]) wait(ctx context.Context) (T, error) {
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// GetCheckpointNumber returns the latest checkpoint number, served from the
+// cache when WithCheckpointCache is set and the cached value is fresh.
 func (client *Client) GetCheckpointNumber(ctx context.Context) (*CheckpointNumber, error) {
+	cache := &client.checkpointCache
+	if cache.ttl <= 0 {
+		return client.fetchCheckpointNumber(ctx)
+	}
+	cache.mu.Lock()
+	if !cache.fetchedAt.IsZero() && time.Since(cache.fetchedAt) < cache.ttl {
+		number := cache.number
+		cache.mu.Unlock()
+		return &CheckpointNumber{Number: number}, nil
+	}
+	if cache.fetch == nil {
+		cache.fetch = startSharedFetch(ctx, func(ctx context.Context) (int, error) {
+			result, err := client.fetchCheckpointNumber(ctx)
+			return result.Number, err
+		}, func(number int, err error) {
+			cache.mu.Lock()
+			defer cache.mu.Unlock()
+			cache.fetch = nil
+			if err == nil {
+				cache.number = number
+				cache.fetchedAt = time.Now()
+			}
+		})
+	}
+	fetch := cache.fetch
+	cache.mu.Unlock()
+
+	number, err := fetch.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &CheckpointNumber{Number: number}, nil
+}
+
+// ForceRefreshCheckpoint fetches the latest checkpoint number from the node,
+// ignoring and then updating the cache.
+func (client *Client) ForceRefreshCheckpoint(ctx context.Context) (*CheckpointNumber, error) {
+	result, err := client.fetchCheckpointNumber(ctx)
+	if err != nil {
+		return result, err
+	}
+	cache := &client.checkpointCache
+	cache.mu.Lock()
+	cache.number = result.Number
+	cache.fetchedAt = time.Now()
+	cache.mu.Unlock()
+	return result, nil
+}
+
+// RecentCheckpoint returns the checkpoint to put in a payload's
+// RecentCheckpoint: the latest checkpoint minus the WithCheckpointOffset offset,
+// floored at zero.
+func (client *Client) RecentCheckpoint(ctx context.Context) (uint64, error) {
+	checkpoint, err := client.GetCheckpointNumber(ctx)
+	if err != nil {
+		return 0, err
+	}
+	latest := uint64(checkpoint.Number)
+	if latest < client.checkpointOffset {
+		return 0, nil
+	}
+	return latest - client.checkpointOffset, nil
+}
+
+func (client *Client) fetchCheckpointNumber(ctx context.Context) (*CheckpointNumber, error) {
 	result := new(CheckpointNumber)
-	return result, client.GetMethod(ctx, "/v1/checkpoints/number", result)
+	if err := client.GetMethod(ctx, "/v1/checkpoints/number", result); err != nil {
+		return result, err
+	}
+	client.observeCheckpoint(uint64(result.Number))
+	return result, nil
+}
+
+// observeCheckpoint records number as the latest known checkpoint if it is newer.
+func (client *Client) observeCheckpoint(number uint64) {
+	for {
+		latest := client.latestCheckpoint.Load()
+		if number <= latest || client.latestCheckpoint.CompareAndSwap(latest, number) {
+			return
+		}
+	}
+}
+
+// CheckRecentCheckpoint validates a payload's RecentCheckpoint against the latest
+// checkpoint seen by this client. It is a no-op unless WithMaxCheckpointAge is set
+// and GetCheckpointNumber has been called at least once.
+func (client *Client) CheckRecentCheckpoint(recentCheckpoint uint64) error {
+	latest := client.latestCheckpoint.Load()
+	if client.maxCheckpointAge == 0 || latest == 0 || recentCheckpoint >= latest {
+		return nil
+	}
+	if age := latest - recentCheckpoint; age > client.maxCheckpointAge {
+		return fmt.Errorf("%w: recent checkpoint %d is %d behind latest %d (max %d)",
+			ErrCheckpointTooStale, recentCheckpoint, age, latest, client.maxCheckpointAge)
+	}
+	return nil
 }
 
 func (client *Client) GetCheckpointByHashFull(ctx context.Context, hash string) (*CheckpointDetailFull, error) {
@@ -0,0 +1,210 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxCheckpointAge(t *testing.T) {
+	var paymentCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/checkpoints/number":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"number":1000}`)
+		case "/v1/transactions/payment":
+			atomic.AddInt32(&paymentCalls, 1)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"hash":"0xabc"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithMaxCheckpointAge(50))
+
+	// Without a known latest checkpoint the check is skipped.
+	if err := client.CheckRecentCheckpoint(1); err != nil {
+		t.Fatalf("Expected no error before any checkpoint was fetched, got: %v", err)
+	}
+
+	if _, err := client.GetCheckpointNumber(context.Background()); err != nil {
+		t.Fatalf("GetCheckpointNumber failed: %v", err)
+	}
+
+	req := &PaymentRequest{PaymentPayload: PaymentPayload{RecentCheckpoint: 990}}
+	if _, err := client.SendPayment(context.Background(), req); err != nil {
+		t.Fatalf("Expected a fresh checkpoint to pass, got: %v", err)
+	}
+
+	req.RecentCheckpoint = 900
+	_, err := client.SendPayment(context.Background(), req)
+	if !errors.Is(err, ErrCheckpointTooStale) {
+		t.Fatalf("Expected ErrCheckpointTooStale, got: %v", err)
+	}
+	if paymentCalls != 1 {
+		t.Errorf("Expected only the fresh payment to reach the server, got %d calls", paymentCalls)
+	}
+}
+
+func TestWithCheckpointCache(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		time.Sleep(5 * time.Millisecond)
+		fmt.Fprintf(w, `{"number":%d}`, 100+n)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithCheckpointCache(time.Minute))
+	var wg sync.WaitGroup
+	numbers := make([]int, 50)
+	for i := range numbers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cp, err := client.GetCheckpointNumber(context.Background())
+			if err != nil {
+				t.Errorf("GetCheckpointNumber failed: %v", err)
+				return
+			}
+			numbers[i] = cp.Number
+		}(i)
+	}
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("Expected 1 request for 50 concurrent calls, got %d", got)
+	}
+	for i, n := range numbers {
+		if n != 101 {
+			t.Errorf("call %d got checkpoint %d, want 101", i, n)
+		}
+	}
+
+	cp, err := client.ForceRefreshCheckpoint(context.Background())
+	if err != nil {
+		t.Fatalf("ForceRefreshCheckpoint failed: %v", err)
+	}
+	if cp.Number != 102 || requests.Load() != 2 {
+		t.Errorf("ForceRefreshCheckpoint = %d after %d requests, want 102 after 2", cp.Number, requests.Load())
+	}
+	if cp, _ := client.GetCheckpointNumber(context.Background()); cp.Number != 102 {
+		t.Errorf("cache should hold the refreshed value, got %d", cp.Number)
+	}
+	if client.latestCheckpoint.Load() != 102 {
+		t.Errorf("latest checkpoint = %d, want 102", client.latestCheckpoint.Load())
+	}
+}
+
+func TestWithCheckpointCache_Expiry(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"number":%d}`, requests.Add(1))
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithCheckpointCache(20*time.Millisecond))
+	client.GetCheckpointNumber(context.Background())
+	client.GetCheckpointNumber(context.Background())
+	if requests.Load() != 1 {
+		t.Fatalf("Expected a cache hit within the TTL, got %d requests", requests.Load())
+	}
+	time.Sleep(30 * time.Millisecond)
+	if cp, _ := client.GetCheckpointNumber(context.Background()); cp.Number != 2 {
+		t.Errorf("Expected a refetch after the TTL, got checkpoint %d", cp.Number)
+	}
+
+	uncached := newClientInternal(server.URL, WithTimeout(time.Second))
+	uncached.GetCheckpointNumber(context.Background())
+	uncached.GetCheckpointNumber(context.Background())
+	if requests.Load() != 4 {
+		t.Errorf("Without WithCheckpointCache every call should hit the node, got %d requests", requests.Load())
+	}
+}
+
+func TestWithCheckpointCache_WaitHonoursContext(t *testing.T) {
+	var requests atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		fmt.Fprintln(w, `{"number":7}`)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := newClientInternal(server.URL, WithTimeout(5*time.Second), WithCheckpointCache(time.Minute))
+	first := make(chan int, 1)
+	go func() {
+		cp, err := client.GetCheckpointNumber(context.Background())
+		if err != nil {
+			t.Errorf("GetCheckpointNumber failed: %v", err)
+			first <- 0
+			return
+		}
+		first <- cp.Number
+	}()
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A second caller joins the slow fetch but gives up at its own deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetCheckpointNumber(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Waiter blocked for %v despite its deadline", elapsed)
+	}
+
+	release <- struct{}{}
+	if n := <-first; n != 7 {
+		t.Errorf("First caller got checkpoint %d, want 7", n)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("Expected the callers to share 1 request, got %d", requests.Load())
+	}
+}
+
+func TestWithCheckpointOffset(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"number":1000}`)
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want uint64
+	}{
+		{"default", nil, 1000},
+		{"offset", []ClientOption{WithCheckpointOffset(3)}, 997},
+		{"offset past genesis", []ClientOption{WithCheckpointOffset(5000)}, 0},
+	}
+	for _, tt := range tests {
+		client := newClientInternal(server.URL, append([]ClientOption{WithTimeout(time.Second)}, tt.opts...)...)
+		got, err := client.RecentCheckpoint(ctx)
+		if err != nil {
+			t.Fatalf("%s: RecentCheckpoint failed: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: RecentCheckpoint = %d, want %d", tt.name, got, tt.want)
+		}
+		// The latest checkpoint itself is unaffected.
+		if latest, _ := client.GetCheckpointNumber(ctx); latest.Number != 1000 {
+			t.Errorf("%s: GetCheckpointNumber = %d, want 1000", tt.name, latest.Number)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package onemoney
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ClientNameHeader carries the WithClientName name on every request so node
+// logs can attribute traffic.
+const ClientNameHeader = "X-Client-Name"
+
+type clientNameCtxKey struct{}
+
+// WithClientName tags the client's traffic with name: it is sent as the
+// X-Client-Name header, prefixed to every line given to the Logger, and
+// available to hooks through ClientNameFromContext. Useful when many
+// processes share the same nodes.
+func WithClientName(name string) ClientOption {
+	return func(c *Client) {
+		c.clientName = name
+	}
+}
+
+// ClientNameFromContext returns the WithClientName name of the client making
+// the request. Hooks receive a context carrying it.
+func ClientNameFromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(clientNameCtxKey{}).(string)
+	return name, ok && name != ""
+}
+
+// withClientName returns ctx tagged with the client's name, if it has one.
+func (client *Client) withClientName(ctx context.Context) context.Context {
+	if client.clientName == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, clientNameCtxKey{}, client.clientName)
+}
+
+func (client *Client) setClientNameHeader(req *http.Request) {
+	if client.clientName != "" {
+		req.Header.Set(ClientNameHeader, client.clientName)
+	}
+}
+
+// namedLogger prefixes every message with the client's name.
+type namedLogger struct {
+	Logger
+	prefix string // a format string fragment, with % escaped
+}
+
+func newNamedLogger(logger Logger, name string) namedLogger {
+	return namedLogger{Logger: logger, prefix: "[" + strings.ReplaceAll(name, "%", "%%") + "] "}
+}
+
+func (l namedLogger) Printf(format string, v ...interface{}) { l.Logger.Printf(l.prefix+format, v...) }
+func (l namedLogger) Infof(format string, v ...interface{})  { l.Logger.Infof(l.prefix+format, v...) }
+func (l namedLogger) Warnf(format string, v ...interface{})  { l.Logger.Warnf(l.prefix+format, v...) }
+func (l namedLogger) Errorf(format string, v ...interface{}) { l.Logger.Errorf(l.prefix+format, v...) }
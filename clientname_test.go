@@ -0,0 +1,67 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithClientName(t *testing.T) {
+	var headers []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Get(ClientNameHeader))
+		fmt.Fprintln(w, `{"chain_id":1,"hash":"0xabc"}`)
+	}))
+	defer server.Close()
+
+	logger := newMockLogger(t)
+	hook := newMockHook(t)
+	// The logger prefix is applied after all options, so option order does not matter.
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithClientName("runner-3"), WithLogger(logger), WithHooks(hook))
+
+	if _, err := client.GetChainId(context.Background()); err != nil {
+		t.Fatalf("GetChainId failed: %v", err)
+	}
+	var result PaymentResponse
+	if err := client.PostMethod(context.Background(), "/v1/transactions/payment", struct{}{}, &result); err != nil {
+		t.Fatalf("PostMethod failed: %v", err)
+	}
+
+	if fmt.Sprint(headers) != "[runner-3 runner-3]" {
+		t.Errorf("%s headers = %v", ClientNameHeader, headers)
+	}
+	for _, line := range logger.getInfofCalls() {
+		if !strings.HasPrefix(line, "[runner-3] ") {
+			t.Errorf("log line without client name: %q", line)
+		}
+	}
+	for _, call := range hook.getPostRequestCalls() {
+		if name, ok := ClientNameFromContext(call.ctx); !ok || name != "runner-3" {
+			t.Errorf("hook context name = %q, %v", name, ok)
+		}
+	}
+
+	// A % in the name must not be read as a formatting verb.
+	percent := newMockLogger(t)
+	newClientInternal(server.URL, WithTimeout(time.Second), WithClientName("node-50%"), WithLogger(percent)).logger.Infof("GET %s", "/x")
+	if lines := percent.getInfofCalls(); len(lines) != 1 || lines[0] != "[node-50%] GET /x" {
+		t.Errorf("log lines = %q, want [\"[node-50%%] GET /x\"]", lines)
+	}
+
+	unnamed := newClientInternal(server.URL, WithTimeout(time.Second), WithHooks(hook))
+	hook.reset()
+	headers = nil
+	if _, err := unnamed.GetChainId(context.Background()); err != nil {
+		t.Fatalf("GetChainId failed: %v", err)
+	}
+	if headers[0] != "" {
+		t.Errorf("unnamed client sent %s %q", ClientNameHeader, headers[0])
+	}
+	if _, ok := ClientNameFromContext(hook.getPostRequestCalls()[0].ctx); ok {
+		t.Error("unnamed client should not tag the hook context")
+	}
+}
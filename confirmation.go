@@ -0,0 +1,161 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultConfirmationPollInterval is how often a CheckpointConfirmationTracker
+// polls GetCheckpointNumber when no interval is given.
+const DefaultConfirmationPollInterval = 2 * time.Second
+
+// ErrTransactionNotIncluded is reported by a CheckpointConfirmationTracker for
+// a transaction still not on chain once its checkpoint search span has passed.
+var ErrTransactionNotIncluded = errors.New("transaction not included on chain")
+
+// CheckpointConfirmationTracker confirms submitted transactions in bulk by
+// watching the latest checkpoint number instead of polling each receipt.
+// Once the network's checkpoint number advances past the checkpoint a
+// transaction was tracked at, its inclusion is checked with a
+// CheckpointBatchVerifier, which reads whole checkpoints for all such
+// transactions at once.
+type CheckpointConfirmationTracker struct {
+	client   *Client
+	verifier *CheckpointBatchVerifier
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending map[uint64][]trackedTransaction
+	err     error // set once the tracker has stopped
+}
+
+type trackedTransaction struct {
+	hash string
+	ch   chan error
+}
+
+// NewCheckpointConfirmationTracker starts a tracker polling client every interval
+// until ctx is done. Transactions still pending when ctx is done receive ctx.Err().
+func NewCheckpointConfirmationTracker(ctx context.Context, client *Client, interval time.Duration) *CheckpointConfirmationTracker {
+	if interval <= 0 {
+		interval = DefaultConfirmationPollInterval
+	}
+	tracker := &CheckpointConfirmationTracker{
+		client:   client,
+		verifier: NewCheckpointBatchVerifier(client),
+		interval: interval,
+		pending:  make(map[uint64][]trackedTransaction),
+	}
+	go tracker.run(ctx)
+	return tracker
+}
+
+// Track registers the transaction txHash, signed with RecentCheckpoint
+// submittedCheckpoint. The returned channel receives nil once the transaction
+// is found on chain, ErrTransactionNotIncluded if it is still missing once the
+// checkpoint number passes submittedCheckpoint plus DefaultCheckpointSearchSpan,
+// or the reason the tracker stopped, and is then closed.
+func (t *CheckpointConfirmationTracker) Track(txHash string, submittedCheckpoint uint64) <-chan error {
+	ch := make(chan error, 1)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil {
+		ch <- t.err
+		close(ch)
+		return ch
+	}
+	t.pending[submittedCheckpoint] = append(t.pending[submittedCheckpoint], trackedTransaction{hash: txHash, ch: ch})
+	return ch
+}
+
+func (t *CheckpointConfirmationTracker) run(ctx context.Context) {
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			t.stop(ctx.Err())
+			return
+		case <-ticker.C:
+			latest, err := t.client.GetCheckpointNumber(ctx)
+			if err != nil {
+				if t.client.logEnabled(LogLevelWarn) {
+					t.client.logger.Warnf("Confirmation tracker failed to fetch checkpoint number: %v", err)
+				}
+				continue
+			}
+			t.confirmBefore(ctx, uint64(latest.Number))
+		}
+	}
+}
+
+// confirmBefore checks the inclusion of every transaction tracked at a
+// checkpoint lower than latest and resolves those with a final answer.
+func (t *CheckpointConfirmationTracker) confirmBefore(ctx context.Context, latest uint64) {
+	t.mu.Lock()
+	var txs []PendingTransaction
+	for checkpoint, tracked := range t.pending {
+		if checkpoint >= latest {
+			continue
+		}
+		for _, tx := range tracked {
+			txs = append(txs, PendingTransaction{Hash: tx.hash, RecentCheckpoint: checkpoint})
+		}
+	}
+	t.mu.Unlock()
+	if len(txs) == 0 {
+		return
+	}
+
+	verified, err := t.verifier.Verify(ctx, txs)
+	if err != nil {
+		if t.client.logEnabled(LogLevelWarn) {
+			t.client.logger.Warnf("Confirmation tracker failed to verify transactions: %v", err)
+		}
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for checkpoint, tracked := range t.pending {
+		if checkpoint >= latest {
+			continue
+		}
+		expired := checkpoint+t.verifier.Span < latest
+		remaining := tracked[:0]
+		for _, tx := range tracked {
+			included, checked := verified[tx.hash]
+			switch {
+			case included:
+				tx.ch <- nil
+				close(tx.ch)
+			case checked && expired:
+				tx.ch <- fmt.Errorf("%w: %s", ErrTransactionNotIncluded, tx.hash)
+				close(tx.ch)
+			default:
+				remaining = append(remaining, tx)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(t.pending, checkpoint)
+		} else {
+			t.pending[checkpoint] = remaining
+		}
+	}
+}
+
+func (t *CheckpointConfirmationTracker) stop(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.err = err
+	for checkpoint, tracked := range t.pending {
+		for _, tx := range tracked {
+			tx.ch <- err
+			close(tx.ch)
+		}
+		delete(t.pending, checkpoint)
+	}
+}
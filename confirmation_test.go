@@ -0,0 +1,101 @@
+package onemoney
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckpointConfirmationTracker(t *testing.T) {
+	var checkpoint atomic.Int64
+	checkpoint.Store(10)
+	// 0x01 and 0x02 land in checkpoints 10 and 11; 0x03 is dropped by the node.
+	byCheckpoint := map[string][]string{"10": {"0x01"}, "11": {"0x02"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/checkpoints/number":
+			fmt.Fprintf(w, `{"number":%d}`, checkpoint.Load())
+		case "/v1/checkpoints/by_number":
+			hashes := byCheckpoint[r.URL.Query().Get("number")]
+			if hashes == nil {
+				hashes = []string{}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"transactions": hashes})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND","message":"receipt not found"}`)
+		}
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	tracker := NewCheckpointConfirmationTracker(ctx, client, 10*time.Millisecond)
+	tracker.verifier.Span = 2
+
+	early := []<-chan error{
+		tracker.Track("0x01", 10),
+		tracker.Track("0x02", 10),
+	}
+	dropped := tracker.Track("0x03", 10)
+	late := tracker.Track("0x04", 20)
+
+	time.Sleep(50 * time.Millisecond)
+	for i, ch := range early {
+		select {
+		case <-ch:
+			t.Fatalf("Transaction %d confirmed before the checkpoint advanced", i)
+		default:
+		}
+	}
+
+	checkpoint.Store(11)
+	for i, ch := range early {
+		select {
+		case err := <-ch:
+			if err != nil {
+				t.Errorf("Transaction %d: expected confirmation, got %v", i, err)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Transaction %d was not confirmed after the checkpoint advanced", i)
+		}
+	}
+
+	select {
+	case err := <-dropped:
+		t.Fatalf("Dropped transaction resolved within its search span: %v", err)
+	default:
+	}
+	checkpoint.Store(13)
+	select {
+	case err := <-dropped:
+		if !errors.Is(err, ErrTransactionNotIncluded) {
+			t.Errorf("Expected ErrTransactionNotIncluded for a dropped transaction, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Dropped transaction was not reported after its search span")
+	}
+
+	select {
+	case <-late:
+		t.Fatal("Transaction at checkpoint 20 confirmed too early")
+	default:
+	}
+
+	cancel()
+	select {
+	case err := <-late:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled for a pending transaction, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Pending transaction was not released when the tracker stopped")
+	}
+}
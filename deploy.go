@@ -0,0 +1,226 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AuthorityEntry is a mint/burn authority to grant during DeployToken, with the
+// mint allowance it receives.
+type AuthorityEntry struct {
+	Address   string
+	Allowance *big.Int
+}
+
+// DeploymentConfig describes a token to set up with DeployToken.
+type DeploymentConfig struct {
+	Symbol    string
+	Name      string
+	Decimals  uint8
+	IsPrivate bool
+	// MintAuthorities are granted MintBurnTokens after issuance. To mint
+	// InitialSupply, the operator must be one of them with enough allowance.
+	MintAuthorities []AuthorityEntry
+	// InitialSupply, if positive, is minted by the operator to InitialRecipient
+	// (the operator when empty) once the authorities are in place.
+	InitialSupply    *big.Int
+	InitialRecipient string
+	// ChainID is fetched from the node when zero.
+	ChainID uint64
+}
+
+// DeployedToken reports what DeployToken did.
+type DeployedToken struct {
+	Address         string
+	IssueHash       string
+	AuthorityHashes []string
+	MintHash        string
+}
+
+// DeployToken issues a token with the operator as master authority, grants the
+// operator MasterMintBurn, grants every MintAuthorities entry MintBurnTokens and
+// finally mints InitialSupply. Each step waits for its receipt before the next
+// starts. If a step fails, the returned DeployedToken records the steps that
+// completed, so a partial deployment can be resumed by hand.
+func (client *Client) DeployToken(ctx context.Context, operatorKey string, cfg *DeploymentConfig, opts ...WaitOption) (*DeployedToken, error) {
+	signer, err := NewLocalSigner(operatorKey)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range cfg.MintAuthorities {
+		if !common.IsHexAddress(entry.Address) {
+			return nil, fmt.Errorf("deploy %s: invalid mint authority address %q", cfg.Symbol, entry.Address)
+		}
+	}
+	recipient := signer.Address()
+	if cfg.InitialRecipient != "" {
+		if !common.IsHexAddress(cfg.InitialRecipient) {
+			return nil, fmt.Errorf("deploy %s: invalid initial recipient %q", cfg.Symbol, cfg.InitialRecipient)
+		}
+		recipient = common.HexToAddress(cfg.InitialRecipient)
+	}
+
+	waitOpts := applyWaitOptions(opts)
+	chainID := cfg.ChainID
+	if chainID == 0 {
+		chain, err := client.GetChainId(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("get chain id: %w", err)
+		}
+		chainID = uint64(chain.ChainId)
+	}
+
+	deployed := new(DeployedToken)
+	token, issueHash, err := client.issueTokenAndWait(ctx, IssueParams{
+		Symbol:    cfg.Symbol,
+		Name:      cfg.Name,
+		Decimals:  cfg.Decimals,
+		IsPrivate: cfg.IsPrivate,
+		ChainID:   chainID,
+	}, signer, waitOpts)
+	deployed.IssueHash = issueHash
+	if err != nil {
+		return deployed, fmt.Errorf("deploy %s: issue: %w", cfg.Symbol, err)
+	}
+	deployed.Address = token.Hex()
+
+	grants := append([]AuthorityEntry{{Address: signer.Address().Hex()}}, cfg.MintAuthorities...)
+	for i, entry := range grants {
+		authorityType := AuthorityTypeMintBurnTokens
+		if i == 0 {
+			authorityType = AuthorityTypeMasterMintBurn
+		}
+		hash, err := client.grantAndWait(ctx, signer, chainID, token, authorityType, entry, waitOpts)
+		if err != nil {
+			return deployed, fmt.Errorf("deploy %s: grant %s to %s: %w", cfg.Symbol, authorityType, entry.Address, err)
+		}
+		deployed.AuthorityHashes = append(deployed.AuthorityHashes, hash)
+	}
+
+	if cfg.InitialSupply != nil && cfg.InitialSupply.Sign() > 0 {
+		_, nonce, checkpoint, err := client.signingContext(ctx, chainID, signer.Address())
+		if err != nil {
+			return deployed, fmt.Errorf("deploy %s: mint: %w", cfg.Symbol, err)
+		}
+		payload := TokenMintPayload{
+			RecentCheckpoint: checkpoint,
+			ChainID:          chainID,
+			Nonce:            nonce,
+			Recipient:        recipient,
+			Value:            NewTokenValue(cfg.InitialSupply),
+			Token:            token,
+		}
+		signature, err := SignMessageWithSigner(payload, signer)
+		if err != nil {
+			return deployed, err
+		}
+		minted, err := client.MintTokenAndWait(ctx, &MintTokenRequest{TokenMintPayload: payload, Signature: *signature}, WithWaitOpts(waitOpts))
+		if err != nil {
+			return deployed, fmt.Errorf("deploy %s: mint: %w", cfg.Symbol, err)
+		}
+		deployed.MintHash = minted.Hash
+	}
+	return deployed, nil
+}
+
+func (client *Client) grantAndWait(ctx context.Context, signer Signer, chainID uint64, token common.Address,
+	authorityType AuthorityType, entry AuthorityEntry, waitOpts WaitOpts) (string, error) {
+	_, nonce, checkpoint, err := client.signingContext(ctx, chainID, signer.Address())
+	if err != nil {
+		return "", err
+	}
+	payload := grantPayload(chainID, nonce, checkpoint, token, authorityType, entry)
+	signature, err := SignMessageWithSigner(payload, signer)
+	if err != nil {
+		return "", err
+	}
+	granted, err := client.GrantTokenAuthorityAndWait(ctx, &TokenAuthorityRequest{TokenAuthorityPayload: payload, Signature: *signature}, WithWaitOpts(waitOpts))
+	if err != nil {
+		return "", err
+	}
+	return granted.Hash, nil
+}
+
+// GrantResult is the outcome of one grant submitted by
+// GrantTokenAuthoritiesPipelined.
+type GrantResult struct {
+	ConfirmedTransaction
+	// Address is the AuthorityEntry address the grant was for.
+	Address string
+	// Err is set when the grant did not confirm successfully. Receipt is nil
+	// when no receipt was seen at all.
+	Err error
+}
+
+// GrantTokenAuthoritiesPipelined grants authorityType on token to every entry
+// without waiting between grants: it signs them with consecutive nonces starting
+// at the signer's next nonce, submits them all, and then waits for every
+// receipt. This takes roughly one confirmation instead of one per entry.
+//
+// If a submission fails the later grants are not sent, since their nonces
+// would leave a gap. The grants already sent are still awaited. The result
+// holds one entry per submitted grant, in the order of entries, each carrying
+// its own error; the errors are also reported joined.
+func (client *Client) GrantTokenAuthoritiesPipelined(ctx context.Context, signer Signer, chainID uint64, token common.Address,
+	authorityType AuthorityType, entries []AuthorityEntry, opts ...WaitOption) ([]GrantResult, error) {
+	chainID, nonce, checkpoint, err := client.signingContext(ctx, chainID, signer.Address())
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	var errs []error
+	hashes := make([]string, 0, len(entries))
+	for i, entry := range entries {
+		payload := grantPayload(chainID, nonce+uint64(i), checkpoint, token, authorityType, entry)
+		signature, err := SignMessageWithSigner(payload, signer)
+		if err != nil {
+			errs = append(errs, err)
+			break
+		}
+		resp, err := client.GrantTokenAuthority(ctx, &TokenAuthorityRequest{TokenAuthorityPayload: payload, Signature: *signature})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("grant %s to %s: %w", authorityType, entry.Address, err))
+			break
+		}
+		hashes = append(hashes, resp.Hash)
+	}
+
+	waitOpts := applyWaitOptions(opts)
+	results := make([]GrantResult, 0, len(hashes))
+	for i, hash := range hashes {
+		receipt, err := client.waitForSuccess(ctx, hash, waitOpts)
+		result := GrantResult{ConfirmedTransaction: ConfirmedTransaction{Hash: hash, Receipt: receipt}, Address: entries[i].Address}
+		if receipt != nil {
+			result.ConfirmationLatency = time.Since(start)
+		}
+		if err != nil {
+			result.Err = fmt.Errorf("grant %s to %s: %w", authorityType, entries[i].Address, err)
+			errs = append(errs, result.Err)
+		}
+		results = append(results, result)
+	}
+	return results, errors.Join(errs...)
+}
+
+func grantPayload(chainID, nonce, checkpoint uint64, token common.Address, authorityType AuthorityType, entry AuthorityEntry) TokenAuthorityPayload {
+	value := entry.Allowance
+	if value == nil {
+		value = new(big.Int)
+	}
+	return TokenAuthorityPayload{
+		RecentCheckpoint: checkpoint,
+		ChainID:          chainID,
+		Nonce:            nonce,
+		Action:           AuthorityActionGrant,
+		AuthorityType:    authorityType,
+		AuthorityAddress: common.HexToAddress(entry.Address),
+		Token:            token,
+		Value:            NewTokenValue(value),
+	}
+}
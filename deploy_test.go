@@ -0,0 +1,214 @@
+package onemoney
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDeployToken(t *testing.T) {
+	const token = "0x00000000000000000000000000000000000000AB"
+	var (
+		mu    sync.Mutex
+		calls []string
+		nonce int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/chains/chain_id":
+			fmt.Fprintln(w, `{"chain_id":1212101}`)
+		case "/v1/accounts/nonce":
+			fmt.Fprintf(w, `{"nonce":%d}`, nonce)
+		case "/v1/checkpoints/number":
+			fmt.Fprintln(w, `{"number":50}`)
+		case "/v1/tokens/issue", "/v1/tokens/mint":
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			calls = append(calls, fmt.Sprintf("%s nonce=%v", r.URL.Path, body["nonce"]))
+			nonce++
+			fmt.Fprintf(w, `{"hash":"0x%d"}`, nonce)
+		case "/v1/tokens/grant_authority":
+			var body TokenAuthorityPayload
+			json.NewDecoder(r.Body).Decode(&body)
+			calls = append(calls, fmt.Sprintf("%s %s %s %s nonce=%d", r.URL.Path, body.AuthorityType, body.AuthorityAddress.Hex(), body.Value, body.Nonce))
+			nonce++
+			fmt.Fprintf(w, `{"hash":"0x%d"}`, nonce)
+		case "/v1/transactions/receipt/by_hash":
+			fmt.Fprintf(w, `{"transaction_hash":%q,"success":true,"token_address":%q}`, r.URL.Query().Get("hash"), token)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	operator, _ := PrivateKeyToAddress(testPrivateKey)
+	minter := "0x0000000000000000000000000000000000000002"
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	deployed, err := client.DeployToken(context.Background(), testPrivateKey, &DeploymentConfig{
+		Symbol:   "USDD",
+		Name:     "USD Deploy",
+		Decimals: 6,
+		MintAuthorities: []AuthorityEntry{
+			{Address: operator, Allowance: big.NewInt(1000)},
+			{Address: minter, Allowance: big.NewInt(500)},
+		},
+		InitialSupply: big.NewInt(1000),
+	}, WithWaitOpts(fastWait))
+	if err != nil {
+		t.Fatalf("DeployToken failed: %v", err)
+	}
+
+	want := []string{
+		"/v1/tokens/issue nonce=0",
+		"/v1/tokens/grant_authority MasterMintBurn " + operator + " 0 nonce=1",
+		"/v1/tokens/grant_authority MintBurnTokens " + operator + " 1000 nonce=2",
+		"/v1/tokens/grant_authority MintBurnTokens " + minter + " 500 nonce=3",
+		"/v1/tokens/mint nonce=4",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("call order:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+	if deployed.Address != token || deployed.IssueHash != "0x1" || deployed.MintHash != "0x5" {
+		t.Errorf("unexpected result: %+v", deployed)
+	}
+	if len(deployed.AuthorityHashes) != 3 || deployed.AuthorityHashes[2] != "0x4" {
+		t.Errorf("AuthorityHashes = %v", deployed.AuthorityHashes)
+	}
+}
+
+func TestDeployToken_PartialFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/nonce":
+			fmt.Fprintln(w, `{"nonce":0}`)
+		case "/v1/checkpoints/number":
+			fmt.Fprintln(w, `{"number":50}`)
+		case "/v1/tokens/issue":
+			fmt.Fprintln(w, `{"hash":"0xissue","token":"0x00000000000000000000000000000000000000AB"}`)
+		case "/v1/tokens/grant_authority":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, `{"error_code":"UNAUTHORIZED","message":"not master"}`)
+		case "/v1/transactions/receipt/by_hash":
+			fmt.Fprintln(w, `{"transaction_hash":"0xissue","success":true}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	deployed, err := client.DeployToken(context.Background(), testPrivateKey,
+		&DeploymentConfig{Symbol: "USDD", Decimals: 6, ChainID: 1212101}, WithWaitOpts(fastWait))
+	if !IsErrorCode(err, ErrCodeUnauthorized) {
+		t.Fatalf("Expected UNAUTHORIZED, got %v", err)
+	}
+	if deployed == nil || deployed.IssueHash != "0xissue" || deployed.Address == "" || len(deployed.AuthorityHashes) != 0 {
+		t.Errorf("partial result should record the issued token: %+v", deployed)
+	}
+
+	if _, err := client.DeployToken(context.Background(), testPrivateKey,
+		&DeploymentConfig{Symbol: "BAD", MintAuthorities: []AuthorityEntry{{Address: "nope"}}}); err == nil {
+		t.Error("Expected error for invalid authority address")
+	}
+}
+
+func TestGrantTokenAuthoritiesPipelined(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	rejected := "0x0000000000000000000000000000000000000004"
+	reverted := "0x0000000000000000000000000000000000000003"
+	missingReceipt := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/accounts/nonce":
+			fmt.Fprintln(w, `{"nonce":7}`)
+		case "/v1/checkpoints/number":
+			fmt.Fprintln(w, `{"number":50}`)
+		case "/v1/tokens/grant_authority":
+			var body TokenAuthorityPayload
+			json.NewDecoder(r.Body).Decode(&body)
+			if body.AuthorityAddress.Hex() == rejected {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintln(w, `{"error_code":"UNAUTHORIZED","message":"not master"}`)
+				return
+			}
+			events = append(events, fmt.Sprintf("grant nonce=%d", body.Nonce))
+			fmt.Fprintf(w, `{"hash":"0x%d"}`, body.Nonce)
+		case "/v1/transactions/receipt/by_hash":
+			hash := r.URL.Query().Get("hash")
+			if hash == missingReceipt {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprintln(w, `{"error_code":"TRANSACTION_NOT_FOUND","message":"not yet"}`)
+				return
+			}
+			events = append(events, "receipt "+hash)
+			fmt.Fprintf(w, `{"transaction_hash":%q,"success":%t,"revert_reason":"limit"}`, hash, hash != "0x9")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	signer, _ := NewLocalSigner(testPrivateKey)
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	token := signer.Address()
+	entries := []AuthorityEntry{
+		{Address: "0x0000000000000000000000000000000000000001", Allowance: big.NewInt(10)},
+		{Address: "0x0000000000000000000000000000000000000002", Allowance: big.NewInt(20)},
+		{Address: reverted, Allowance: big.NewInt(30)},
+	}
+	results, err := client.GrantTokenAuthoritiesPipelined(context.Background(), signer, 1212101, token, AuthorityTypeMintBurnTokens, entries, WithWaitOpts(fastWait))
+	if !errors.Is(err, ErrTransactionFailed) || !strings.Contains(err.Error(), reverted) {
+		t.Fatalf("Expected ErrTransactionFailed naming %s, got %v", reverted, err)
+	}
+	if len(results) != 3 || results[2].Receipt.Success || results[0].Hash != "0x7" {
+		t.Fatalf("Unexpected results: %+v", results)
+	}
+	if results[2].Address != reverted || !errors.Is(results[2].Err, ErrTransactionFailed) || results[0].Err != nil {
+		t.Errorf("Unexpected per-grant results: %+v", results)
+	}
+	want := "grant nonce=7,grant nonce=8,grant nonce=9,receipt 0x7,receipt 0x8,receipt 0x9"
+	if got := strings.Join(events, ","); got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+
+	// A rejected submission stops the later grants, but those already sent are awaited.
+	events = nil
+	entries = []AuthorityEntry{entries[0], {Address: rejected}, entries[1]}
+	results, err = client.GrantTokenAuthoritiesPipelined(context.Background(), signer, 1212101, token, AuthorityTypeMintBurnTokens, entries, WithWaitOpts(fastWait))
+	if !IsErrorCode(err, ErrCodeUnauthorized) {
+		t.Fatalf("Expected UNAUTHORIZED, got %v", err)
+	}
+	if len(results) != 1 || strings.Join(events, ",") != "grant nonce=7,receipt 0x7" {
+		t.Errorf("Unexpected results %+v and events %v", results, events)
+	}
+
+	// A grant whose receipt never appears keeps its place in the results.
+	mu.Lock()
+	events = nil
+	missingReceipt = "0x8"
+	mu.Unlock()
+	entries = []AuthorityEntry{entries[0], entries[2]}
+	results, err = client.GrantTokenAuthoritiesPipelined(context.Background(), signer, 1212101, token, AuthorityTypeMintBurnTokens, entries, WithWaitOpts(fastWait))
+	if !errors.Is(err, ErrReceiptTimeout) {
+		t.Fatalf("Expected ErrReceiptTimeout, got %v", err)
+	}
+	if len(results) != 2 || results[1].Address != entries[1].Address || results[1].Receipt != nil ||
+		!errors.Is(results[1].Err, ErrReceiptTimeout) || results[0].Receipt == nil {
+		t.Errorf("Unexpected results: %+v", results)
+	}
+}
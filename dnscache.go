@@ -0,0 +1,108 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithDNSCache caches resolved IP addresses per host for ttl, so that clients
+// sending many requests to nodes by hostname do not hit the resolver on every
+// new connection. Addresses are tried in the order the resolver returned them.
+//
+// Like WithHTTP2Enabled, the option clones the client's *http.Transport and
+// wraps its DialContext, so apply it after WithHTTPClient; transports that are
+// not *http.Transport are left unchanged.
+func WithDNSCache(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if ttl <= 0 {
+			return
+		}
+		var transport *http.Transport
+		switch rt := c.httpclient.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = rt.Clone()
+		default:
+			return
+		}
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+		}
+		transport.DialContext = newDNSCache(ttl, dial).DialContext
+		c.httpclient.Transport = transport
+	}
+}
+
+type dnsEntry struct {
+	addrs   []net.IPAddr
+	expires time.Time
+}
+
+type dnsCache struct {
+	ttl    time.Duration
+	dial   func(ctx context.Context, network, addr string) (net.Conn, error)
+	lookup func(ctx context.Context, host string) ([]net.IPAddr, error)
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+func newDNSCache(ttl time.Duration, dial func(ctx context.Context, network, addr string) (net.Conn, error)) *dnsCache {
+	return &dnsCache{
+		ttl:     ttl,
+		dial:    dial,
+		lookup:  net.DefaultResolver.LookupIPAddr,
+		now:     time.Now,
+		entries: make(map[string]dnsEntry),
+	}
+}
+
+// DialContext resolves the host of addr through the cache and dials the first
+// address that accepts the connection.
+func (d *dnsCache) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return d.dial(ctx, network, addr)
+	}
+	ips, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var dialErrs []error
+	for _, ip := range ips {
+		conn, err := d.dial(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		dialErrs = append(dialErrs, err)
+	}
+	return nil, errors.Join(dialErrs...)
+}
+
+func (d *dnsCache) resolve(ctx context.Context, host string) ([]net.IPAddr, error) {
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	d.mu.Unlock()
+	if ok && d.now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, &net.DNSError{Err: "no addresses found", Name: host, IsNotFound: true}
+	}
+	d.mu.Lock()
+	d.entries[host] = dnsEntry{addrs: addrs, expires: d.now().Add(d.ttl)}
+	d.mu.Unlock()
+	return addrs, nil
+}
@@ -0,0 +1,92 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDNSCache_DialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	var dialed []string
+	cache := newDNSCache(time.Minute, func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return (&net.Dialer{}).DialContext(ctx, network, addr)
+	})
+	lookups := 0
+	cache.lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		lookups++
+		// The first address refuses connections, so the dialer must fall through.
+		return []net.IPAddr{{IP: net.ParseIP("127.0.0.2")}, {IP: net.ParseIP("127.0.0.1")}}, nil
+	}
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		conn, err := cache.DialContext(context.Background(), "tcp", "node.example:"+port)
+		if err != nil {
+			t.Fatalf("DialContext failed: %v", err)
+		}
+		conn.Close()
+	}
+	if lookups != 1 {
+		t.Errorf("lookups = %d, want 1 within the TTL", lookups)
+	}
+	if dialed[len(dialed)-1] != "127.0.0.1:"+port {
+		t.Errorf("last dialed address = %s", dialed[len(dialed)-1])
+	}
+
+	now = now.Add(2 * time.Minute)
+	conn, err := cache.DialContext(context.Background(), "tcp", "node.example:"+port)
+	if err != nil {
+		t.Fatalf("DialContext after expiry failed: %v", err)
+	}
+	conn.Close()
+	if lookups != 2 {
+		t.Errorf("lookups = %d, want 2 after TTL expiry", lookups)
+	}
+
+	// IP literals bypass the cache.
+	conn, err = cache.DialContext(context.Background(), "tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("DialContext with IP failed: %v", err)
+	}
+	conn.Close()
+	if lookups != 2 {
+		t.Errorf("IP literal triggered a lookup")
+	}
+
+	cache.lookup = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	if _, err := cache.DialContext(context.Background(), "tcp", "other.example:"+port); err == nil {
+		t.Error("expected lookup error to be returned")
+	}
+}
+
+func TestWithDNSCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"number":12}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithDNSCache(time.Minute))
+	transport, ok := client.httpclient.Transport.(*http.Transport)
+	if !ok || transport.DialContext == nil {
+		t.Fatalf("expected a transport with a caching DialContext, got %T", client.httpclient.Transport)
+	}
+	cp, err := client.GetCheckpointNumber(context.Background())
+	if err != nil {
+		t.Fatalf("GetCheckpointNumber failed: %v", err)
+	}
+	if cp.Number != 12 {
+		t.Errorf("Number = %d, want 12", cp.Number)
+	}
+}
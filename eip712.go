@@ -0,0 +1,218 @@
+package onemoney
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultEIP712Name and DefaultEIP712Version make up the domain returned by
+// DefaultDomain.
+const (
+	DefaultEIP712Name    = "1Money Network"
+	DefaultEIP712Version = "1"
+)
+
+var eip712DomainTypeHash = crypto.Keccak256([]byte("EIP712Domain(string name,string version,uint256 chainId)"))
+
+// EIP712Domain is the signing domain mixed into every EIP-712 digest, so a
+// signature for one chain or application cannot be replayed on another.
+type EIP712Domain struct {
+	Name    string
+	Version string
+	ChainID uint64
+}
+
+// DefaultDomain returns the 1Money domain for chainID.
+func DefaultDomain(chainID uint64) EIP712Domain {
+	return EIP712Domain{Name: DefaultEIP712Name, Version: DefaultEIP712Version, ChainID: chainID}
+}
+
+// Separator returns keccak256(abi.encode(typeHash, keccak256(name), keccak256(version), chainId)).
+func (d EIP712Domain) Separator() []byte {
+	chainID := new(big.Int).SetUint64(d.ChainID)
+	return crypto.Keccak256(
+		eip712DomainTypeHash,
+		crypto.Keccak256([]byte(d.Name)),
+		crypto.Keccak256([]byte(d.Version)),
+		common.LeftPadBytes(chainID.Bytes(), 32),
+	)
+}
+
+// EIP712Typed is implemented by payloads that can be signed with SignEIP712.
+// EIP712Type returns the primary type name; the member list is derived from
+// the struct's fields, in declaration order, named after their json tags.
+type EIP712Typed interface {
+	EIP712Type() string
+}
+
+func (TokenIssuePayload) EIP712Type() string           { return "TokenIssue" }
+func (PaymentPayload) EIP712Type() string              { return "Payment" }
+func (TokenMintPayload) EIP712Type() string            { return "TokenMint" }
+func (TokenBurnPayload) EIP712Type() string            { return "TokenBurn" }
+func (TokenAuthorityPayload) EIP712Type() string       { return "TokenAuthority" }
+func (TokenManageListPayload) EIP712Type() string      { return "TokenManageList" }
+func (BatchManageListPayload) EIP712Type() string      { return "BatchTokenManageList" }
+func (PauseTokenPayload) EIP712Type() string           { return "TokenPause" }
+func (SetVelocityLimitPayload) EIP712Type() string     { return "TokenVelocityLimit" }
+func (RevokeAllAuthoritiesPayload) EIP712Type() string { return "TokenRevokeAllAuthorities" }
+
+// EIP712EncodeType returns the encodeType string of payload, for example
+// "Payment(uint64 recent_checkpoint,...,address token)".
+func EIP712EncodeType(payload EIP712Typed) (string, error) {
+	v, err := eip712Struct(payload)
+	if err != nil {
+		return "", err
+	}
+	members := make([]string, 0, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		solType, err := eip712FieldType(field.Type)
+		if err != nil {
+			return "", fmt.Errorf("eip712: field %s: %w", field.Name, err)
+		}
+		members = append(members, solType+" "+eip712FieldName(field))
+	}
+	return payload.EIP712Type() + "(" + strings.Join(members, ",") + ")", nil
+}
+
+// EIP712Digest returns keccak256("\x19\x01" || domainSeparator || hashStruct(payload)).
+func EIP712Digest(payload interface{}, domain EIP712Domain) ([]byte, error) {
+	typed, ok := payload.(EIP712Typed)
+	if !ok {
+		return nil, fmt.Errorf("eip712: %T does not implement EIP712Typed", payload)
+	}
+	encodeType, err := EIP712EncodeType(typed)
+	if err != nil {
+		return nil, err
+	}
+	v, _ := eip712Struct(typed)
+	encoded := [][]byte{crypto.Keccak256([]byte(encodeType))}
+	for i := 0; i < v.NumField(); i++ {
+		word, err := eip712EncodeValue(v.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("eip712: field %s: %w", v.Type().Field(i).Name, err)
+		}
+		encoded = append(encoded, word)
+	}
+	structHash := crypto.Keccak256(encoded...)
+	return crypto.Keccak256([]byte{0x19, 0x01}, domain.Separator(), structHash), nil
+}
+
+// SignEIP712 signs payload as EIP-712 typed data under domain.
+func SignEIP712(payload interface{}, privateKey string, domain EIP712Domain) (*Signature, error) {
+	signer, err := NewLocalSigner(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return SignEIP712WithSigner(payload, signer, domain)
+}
+
+// SignEIP712WithSigner signs payload as EIP-712 typed data, delegating the
+// ECDSA operation to signer.
+func SignEIP712WithSigner(payload interface{}, signer Signer, domain EIP712Domain) (*Signature, error) {
+	digest, err := EIP712Digest(payload, domain)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(digest)
+}
+
+func eip712Struct(payload interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(payload)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("eip712: nil %T", payload)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("eip712: %T is not a struct", payload)
+	}
+	return v, nil
+}
+
+func eip712FieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+var (
+	addressType    = reflect.TypeOf(common.Address{})
+	bigIntType     = reflect.TypeOf((*big.Int)(nil))
+	tokenValueType = reflect.TypeOf(TokenValue{})
+)
+
+func eip712FieldType(t reflect.Type) (string, error) {
+	switch {
+	case t == addressType:
+		return "address", nil
+	case t == bigIntType, t == tokenValueType:
+		return "uint256", nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool", nil
+	case reflect.String:
+		return "string", nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return fmt.Sprintf("uint%d", t.Bits()), nil
+	case reflect.Slice:
+		elem, err := eip712FieldType(t.Elem())
+		if err != nil {
+			return "", err
+		}
+		return elem + "[]", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+func eip712EncodeValue(v reflect.Value) ([]byte, error) {
+	switch {
+	case v.Type() == addressType:
+		addr := v.Interface().(common.Address)
+		return common.LeftPadBytes(addr.Bytes(), 32), nil
+	case v.Type() == bigIntType, v.Type() == tokenValueType:
+		n, ok := v.Interface().(*big.Int)
+		if !ok {
+			n = v.Interface().(TokenValue).Int()
+		}
+		if n == nil {
+			return make([]byte, 32), nil
+		}
+		if n.Sign() < 0 || n.BitLen() > 256 {
+			return nil, fmt.Errorf("value %s out of uint256 range", n)
+		}
+		return common.LeftPadBytes(n.Bytes(), 32), nil
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		word := make([]byte, 32)
+		if v.Bool() {
+			word[31] = 1
+		}
+		return word, nil
+	case reflect.String:
+		return crypto.Keccak256([]byte(v.String())), nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return common.LeftPadBytes(new(big.Int).SetUint64(v.Uint()).Bytes(), 32), nil
+	case reflect.Slice:
+		// Arrays hash the concatenation of their encoded elements.
+		words := make([][]byte, v.Len())
+		for i := range words {
+			word, err := eip712EncodeValue(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			words[i] = word
+		}
+		return crypto.Keccak256(words...), nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", v.Type())
+}
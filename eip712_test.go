@@ -0,0 +1,98 @@
+package onemoney
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Reference values below were computed with an independent Keccak-256
+// implementation following the EIP-712 encoding rules.
+
+func TestEIP712DomainSeparator(t *testing.T) {
+	got := hex.EncodeToString(DefaultDomain(1212101).Separator())
+	if want := "09e0020dac382f3cdfffa1e1030e63c3818f3d4ba1a146afa8e299dfbf578f88"; got != want {
+		t.Errorf("Separator = %s, want %s", got, want)
+	}
+	if got := hex.EncodeToString(eip712DomainTypeHash); got != "c2f8787176b8ac6bf7215b4adcc1e069bf4ab82d9ab1df05a57a91d425935b6e" {
+		t.Errorf("domain type hash = %s", got)
+	}
+}
+
+func TestEIP712Digest(t *testing.T) {
+	payment := PaymentPayload{
+		RecentCheckpoint: 100,
+		ChainID:          1212101,
+		Nonce:            7,
+		Recipient:        common.HexToAddress("0x2"),
+		Value:            NewTokenValue(big.NewInt(1000000)),
+		Token:            common.HexToAddress("0x3"),
+	}
+	encodeType, err := EIP712EncodeType(payment)
+	if err != nil {
+		t.Fatalf("EIP712EncodeType failed: %v", err)
+	}
+	if want := "Payment(uint64 recent_checkpoint,uint64 chain_id,uint64 nonce,address recipient,uint256 value,address token)"; encodeType != want {
+		t.Errorf("encodeType = %s, want %s", encodeType, want)
+	}
+	digest, err := EIP712Digest(payment, DefaultDomain(1212101))
+	if err != nil {
+		t.Fatalf("EIP712Digest failed: %v", err)
+	}
+	if got, want := hex.EncodeToString(digest), "a70af6a04d7809bf79bfdcb9e20b200cffb368eec94cf2f188a2922f83fc0c27"; got != want {
+		t.Errorf("payment digest = %s, want %s", got, want)
+	}
+
+	issue := &TokenIssuePayload{
+		RecentCheckpoint: 5,
+		ChainID:          1,
+		Symbol:           "USDX",
+		Name:             "USD X",
+		Decimals:         6,
+		MasterAuthority:  common.HexToAddress("0xab"),
+		IsPrivate:        true,
+	}
+	digest, err = EIP712Digest(issue, DefaultDomain(1))
+	if err != nil {
+		t.Fatalf("EIP712Digest failed: %v", err)
+	}
+	if got, want := hex.EncodeToString(digest), "dcf0fd305ff82bf534c721290c52bf7e406dcb3c08ce4474a1c8d338c59ff747"; got != want {
+		t.Errorf("issue digest = %s, want %s", got, want)
+	}
+
+	// The same payload on another chain must produce a different digest.
+	other, _ := EIP712Digest(payment, DefaultDomain(1))
+	if hex.EncodeToString(other) == "a70af6a04d7809bf79bfdcb9e20b200cffb368eec94cf2f188a2922f83fc0c27" {
+		t.Error("domain chain id does not affect the digest")
+	}
+}
+
+func TestSignEIP712(t *testing.T) {
+	payment := PaymentPayload{ChainID: 1212101, Nonce: 1, Value: NewTokenValue(big.NewInt(1)), Token: common.HexToAddress("0x3")}
+	domain := DefaultDomain(1212101)
+	sig, err := SignEIP712(payment, testPrivateKey, domain)
+	if err != nil {
+		t.Fatalf("SignEIP712 failed: %v", err)
+	}
+
+	digest, _ := EIP712Digest(payment, domain)
+	raw := append(append(common.HexToHash(sig.R).Bytes(), common.HexToHash(sig.S).Bytes()...), byte(sig.V))
+	pub, err := crypto.SigToPub(digest, raw)
+	if err != nil {
+		t.Fatalf("SigToPub failed: %v", err)
+	}
+	want, _ := PrivateKeyToAddress(testPrivateKey)
+	if got := crypto.PubkeyToAddress(*pub).Hex(); got != want {
+		t.Errorf("recovered %s, want %s", got, want)
+	}
+
+	if _, err := SignEIP712(UpdateMetadataPayload{}, testPrivateKey, domain); err == nil {
+		t.Error("expected error for a payload without EIP712Type")
+	}
+	if _, err := SignEIP712(PaymentPayload{Value: NewTokenValue(big.NewInt(-1))}, testPrivateKey, domain); err == nil {
+		t.Error("expected error for negative uint256")
+	}
+}
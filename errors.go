@@ -0,0 +1,201 @@
+package onemoney
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// Error codes returned by the 1Money API in ErrorResponse.ErrorCode.
+const (
+	ErrCodeNotFound                  = "NOT_FOUND"
+	ErrCodeBadInput                  = "BAD_INPUT"
+	ErrCodeInternalError             = "INTERNAL_ERROR"
+	ErrCodeAccountNotFound           = "ACCOUNT_NOT_FOUND"
+	ErrCodeTokenNotFound             = "TOKEN_NOT_FOUND"
+	ErrCodeTransactionNotFound       = "TRANSACTION_NOT_FOUND"
+	ErrCodeCheckpointNotFound        = "CHECKPOINT_NOT_FOUND"
+	ErrCodeInsufficientBalance       = "INSUFFICIENT_BALANCE"
+	ErrCodeInsufficientMintAllowance = "INSUFFICIENT_MINT_ALLOWANCE"
+	ErrCodeAddressBlacklisted        = "ADDRESS_BLACKLISTED"
+	ErrCodeAddressNotWhitelisted     = "ADDRESS_NOT_WHITELISTED"
+	ErrCodeTokenPaused               = "TOKEN_PAUSED"
+	ErrCodeUnauthorized              = "UNAUTHORIZED"
+	ErrCodeInvalidSignature          = "INVALID_SIGNATURE"
+	ErrCodeInvalidNonce              = "INVALID_NONCE"
+	ErrCodeNonceConflict             = "NONCE_CONFLICT"
+	ErrCodeInvalidChainID            = "INVALID_CHAIN_ID"
+	ErrCodeCheckpointTooOld          = "CHECKPOINT_TOO_OLD"
+	ErrCodeSymbolAlreadyExists       = "SYMBOL_ALREADY_EXISTS"
+	ErrCodeRateLimited               = "RATE_LIMITED"
+)
+
+// IsErrorCode reports whether err is, or wraps, an *APIError carrying the given error code.
+func IsErrorCode(err error, code string) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.ErrorCode == code
+}
+
+// IsNotFound reports whether err is an API error with ErrCodeNotFound.
+func IsNotFound(err error) bool {
+	return IsErrorCode(err, ErrCodeNotFound)
+}
+
+// IsTokenNotFound reports whether err is an API error with ErrCodeTokenNotFound.
+func IsTokenNotFound(err error) bool {
+	return IsErrorCode(err, ErrCodeTokenNotFound)
+}
+
+// IsTransactionNotFound reports whether err is an API error with ErrCodeTransactionNotFound.
+func IsTransactionNotFound(err error) bool {
+	return IsErrorCode(err, ErrCodeTransactionNotFound)
+}
+
+// IsInsufficientBalance reports whether err is an API error with ErrCodeInsufficientBalance.
+func IsInsufficientBalance(err error) bool {
+	return IsErrorCode(err, ErrCodeInsufficientBalance)
+}
+
+// IsInsufficientMintAllowance reports whether err is an API error with ErrCodeInsufficientMintAllowance.
+func IsInsufficientMintAllowance(err error) bool {
+	return IsErrorCode(err, ErrCodeInsufficientMintAllowance)
+}
+
+// IsAddressBlacklisted reports whether err is an API error with ErrCodeAddressBlacklisted.
+func IsAddressBlacklisted(err error) bool {
+	return IsErrorCode(err, ErrCodeAddressBlacklisted)
+}
+
+// IsTokenPaused reports whether err is an API error with ErrCodeTokenPaused.
+func IsTokenPaused(err error) bool {
+	return IsErrorCode(err, ErrCodeTokenPaused)
+}
+
+// IsInvalidSignature reports whether err is an API error with ErrCodeInvalidSignature.
+func IsInvalidSignature(err error) bool {
+	return IsErrorCode(err, ErrCodeInvalidSignature)
+}
+
+// IsNonceConflict reports whether err is an API error signalling a nonce the
+// node would not accept. INVALID_NONCE is also returned for a nonce ahead of
+// the account, so compare with GetAccountNonce before bumping it.
+func IsNonceConflict(err error) bool {
+	return IsErrorCode(err, ErrCodeNonceConflict) || IsErrorCode(err, ErrCodeInvalidNonce)
+}
+
+// IsSymbolTaken reports whether err is an API error with ErrCodeSymbolAlreadyExists,
+// as returned by IssueToken for a symbol that is already in use.
+func IsSymbolTaken(err error) bool {
+	return IsErrorCode(err, ErrCodeSymbolAlreadyExists)
+}
+
+// IsRateLimited reports whether err is an API error with ErrCodeRateLimited.
+func IsRateLimited(err error) bool {
+	return IsErrorCode(err, ErrCodeRateLimited)
+}
+
+// NetworkErrorKind classifies transport-level failures that occur before any
+// HTTP response is received.
+type NetworkErrorKind int
+
+const (
+	NetworkErrorUnknown NetworkErrorKind = iota
+	NetworkErrorTimeout
+	NetworkErrorConnectionRefused
+	NetworkErrorDNSFailure
+	NetworkErrorTLSHandshake
+)
+
+func (k NetworkErrorKind) String() string {
+	switch k {
+	case NetworkErrorTimeout:
+		return "timeout"
+	case NetworkErrorConnectionRefused:
+		return "connection refused"
+	case NetworkErrorDNSFailure:
+		return "dns failure"
+	case NetworkErrorTLSHandshake:
+		return "tls handshake"
+	default:
+		return "unknown"
+	}
+}
+
+// NetworkError is returned by GetMethod and PostMethod when the request could
+// not be delivered to the node, as opposed to an *APIError which means the
+// node answered with a non-200 status.
+type NetworkError struct {
+	Kind   NetworkErrorKind
+	Method string
+	Path   string
+	Err    error
+}
+
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("api %s failed to request path: %s, err: %v", strings.ToLower(e.Method), e.Path, e.Err)
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// ClassifyNetworkError reports which kind of transport failure err represents.
+// DNS and TLS failures are checked first because both may also report themselves
+// as timeouts.
+func ClassifyNetworkError(err error) NetworkErrorKind {
+	if err == nil {
+		return NetworkErrorUnknown
+	}
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return netErr.Kind
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return NetworkErrorDNSFailure
+	}
+
+	var (
+		recordErr    tls.RecordHeaderError
+		alertErr     tls.AlertError
+		verifyErr    *tls.CertificateVerificationError
+		authorityErr x509.UnknownAuthorityError
+		hostnameErr  x509.HostnameError
+		invalidErr   x509.CertificateInvalidError
+	)
+	if errors.As(err, &recordErr) || errors.As(err, &alertErr) || errors.As(err, &verifyErr) ||
+		errors.As(err, &authorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidErr) {
+		return NetworkErrorTLSHandshake
+	}
+
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return NetworkErrorConnectionRefused
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return NetworkErrorTimeout
+	}
+	var timeoutErr interface{ Timeout() bool }
+	if errors.As(err, &timeoutErr) && timeoutErr.Timeout() {
+		return NetworkErrorTimeout
+	}
+	return NetworkErrorUnknown
+}
+
+// IsNetworkErrorKind reports whether err is a transport failure of the given kind.
+func IsNetworkErrorKind(err error, kind NetworkErrorKind) bool {
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) {
+		return false
+	}
+	return netErr.Kind == kind
+}
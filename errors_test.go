@@ -0,0 +1,54 @@
+package onemoney_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	onemoney "github.com/1Money-Co/1money-protocol-go-sdk"
+)
+
+func TestIsErrorCode(t *testing.T) {
+	apiErr := &onemoney.APIError{
+		StatusCode: http.StatusBadRequest,
+		ErrorCode:  onemoney.ErrCodeTokenNotFound,
+		Message:    "token not found",
+	}
+	wrapped := fmt.Errorf("mint failed: %w", apiErr)
+
+	if !onemoney.IsErrorCode(apiErr, onemoney.ErrCodeTokenNotFound) {
+		t.Error("Expected IsErrorCode to match the APIError code")
+	}
+	if !onemoney.IsTokenNotFound(wrapped) {
+		t.Error("Expected IsTokenNotFound to match a wrapped APIError")
+	}
+	if onemoney.IsInsufficientBalance(wrapped) {
+		t.Error("Expected IsInsufficientBalance not to match a different code")
+	}
+	if onemoney.IsErrorCode(errors.New(onemoney.ErrCodeTokenNotFound), onemoney.ErrCodeTokenNotFound) {
+		t.Error("Expected IsErrorCode not to match a plain error")
+	}
+	if onemoney.IsErrorCode(nil, onemoney.ErrCodeTokenNotFound) {
+		t.Error("Expected IsErrorCode not to match a nil error")
+	}
+}
+
+func TestIsNonceConflict(t *testing.T) {
+	for _, code := range []string{onemoney.ErrCodeNonceConflict, onemoney.ErrCodeInvalidNonce} {
+		err := &onemoney.APIError{StatusCode: http.StatusBadRequest, ErrorCode: code}
+		if !onemoney.IsNonceConflict(err) {
+			t.Errorf("Expected IsNonceConflict to match code %s", code)
+		}
+	}
+}
+
+func TestIsSymbolTaken(t *testing.T) {
+	err := fmt.Errorf("issue USDA: %w", &onemoney.APIError{StatusCode: http.StatusBadRequest, ErrorCode: onemoney.ErrCodeSymbolAlreadyExists})
+	if !onemoney.IsSymbolTaken(err) {
+		t.Error("Expected IsSymbolTaken to match a wrapped SYMBOL_ALREADY_EXISTS error")
+	}
+	if onemoney.IsSymbolTaken(&onemoney.APIError{StatusCode: http.StatusBadRequest, ErrorCode: onemoney.ErrCodeBadInput}) {
+		t.Error("Expected IsSymbolTaken not to match BAD_INPUT")
+	}
+}
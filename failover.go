@@ -0,0 +1,192 @@
+package onemoney
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultFailoverProbeInterval is how often a failed-over client checks
+// whether its primary URL has recovered.
+const defaultFailoverProbeInterval = 30 * time.Second
+
+// failoverProbeTimeout bounds a single probe of the primary URL.
+const failoverProbeTimeout = 5 * time.Second
+
+type failover struct {
+	mu            sync.Mutex
+	urls          []string // urls[0] is the primary
+	active        int
+	lastProbe     time.Time
+	probeInterval time.Duration
+}
+
+// WithFallbackURLs adds fallback nodes, in priority order, behind the client's
+// base host. A request that fails with a NetworkError is retried against the
+// next URL, and the URL that answers becomes active for later requests. API
+// errors are returned as is. While failed over, the primary is probed in the
+// background at most every 30 seconds and becomes active again once it answers.
+//
+// POSTs only fail over when the connection was never established (DNS, refused
+// or TLS handshake failures): after a timeout or a dropped connection the
+// transaction may already have reached the node. With WithRetryBudget set,
+// every attempt after the first needs a grant from the budget.
+func WithFallbackURLs(urls ...string) ClientOption {
+	return func(c *Client) {
+		if len(urls) == 0 {
+			return
+		}
+		c.failover = &failover{
+			urls:          append([]string{c.baseHost}, urls...),
+			probeInterval: defaultFailoverProbeInterval,
+		}
+	}
+}
+
+// ActiveURL returns the base URL requests are currently sent to.
+func (client *Client) ActiveURL() string {
+	f := client.failover
+	if f == nil {
+		return client.lastPrimaryHost()
+	}
+	f.mu.Lock()
+	active := f.active
+	f.mu.Unlock()
+	if active == 0 {
+		return client.lastPrimaryHost()
+	}
+	return f.urls[active]
+}
+
+// host returns the base URL at idx in the failover list; index 0 is the
+// primary, which may come from a BaseHostResolver.
+func (f *failover) host(ctx context.Context, client *Client, idx int) (string, error) {
+	if idx == 0 {
+		return client.primaryHost(ctx)
+	}
+	return f.urls[idx], nil
+}
+
+// withFailover runs call against the active URL, moving down the fallback
+// list on network errors that are safe to retry for method.
+func (client *Client) withFailover(ctx context.Context, method string, call func(host string) error) error {
+	f := client.failover
+	if f == nil {
+		host, err := client.primaryHost(ctx)
+		if err != nil {
+			return err
+		}
+		err = call(host)
+		var netErr *NetworkError
+		if errors.As(err, &netErr) {
+			client.invalidateResolvedHost()
+		}
+		return err
+	}
+	client.probePrimary()
+
+	f.mu.Lock()
+	start := f.active
+	f.mu.Unlock()
+
+	var err error
+	for i := 0; i < len(f.urls); i++ {
+		idx := (start + i) % len(f.urls)
+		if i > 0 && client.retryBudget != nil && !client.retryBudget.TryRetry() {
+			return fmt.Errorf("%w, not failing over: %w", ErrRetryBudgetExhausted, err)
+		}
+		var host string
+		host, err = f.host(ctx, client, idx)
+		if err != nil {
+			if client.logEnabled(LogLevelWarn) {
+				client.logger.Warnf("%v, failing over", err)
+			}
+			continue
+		}
+		err = call(host)
+		var netErr *NetworkError
+		if errors.As(err, &netErr) && ctx.Err() == nil {
+			if idx == 0 {
+				client.invalidateResolvedHost()
+			}
+			if method == "POST" && !isConnectFailure(netErr.Kind) {
+				return err
+			}
+			if client.logEnabled(LogLevelWarn) {
+				client.logger.Warnf("Node %s unreachable (%s), failing over", host, netErr.Kind)
+			}
+			continue
+		}
+		if idx != start {
+			client.setActiveURL(idx)
+		}
+		return err
+	}
+	return err
+}
+
+// isConnectFailure reports whether kind means the request never reached the
+// node, so re-sending it elsewhere cannot apply it twice.
+func isConnectFailure(kind NetworkErrorKind) bool {
+	switch kind {
+	case NetworkErrorDNSFailure, NetworkErrorConnectionRefused, NetworkErrorTLSHandshake:
+		return true
+	}
+	return false
+}
+
+// probePrimary starts a background check of the primary URL if the client is
+// failed over and the probe interval has elapsed. The caller's request does
+// not wait for it.
+func (client *Client) probePrimary() {
+	f := client.failover
+	f.mu.Lock()
+	if f.active == 0 || time.Since(f.lastProbe) < f.probeInterval {
+		f.mu.Unlock()
+		return
+	}
+	f.lastProbe = time.Now()
+	f.mu.Unlock()
+	go client.runPrimaryProbe()
+}
+
+// runPrimaryProbe switches back to the primary URL if it answers a chain id
+// request. It talks to the transport directly, so the probe neither fires
+// hooks nor counts against the retry budget.
+func (client *Client) runPrimaryProbe() {
+	ctx, cancel := context.WithTimeout(context.Background(), failoverProbeTimeout)
+	defer cancel()
+	primary, err := client.primaryHost(ctx)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", primary+"/v1/chains/chain_id", nil)
+	if err != nil {
+		return
+	}
+	resp, err := client.httpclient.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	var result ChainIdResponse
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&result) != nil || result.ChainId == 0 {
+		return
+	}
+	client.setActiveURL(0)
+}
+
+func (client *Client) setActiveURL(idx int) {
+	f := client.failover
+	f.mu.Lock()
+	f.active = idx
+	f.lastProbe = time.Now()
+	f.mu.Unlock()
+	if client.logEnabled(LogLevelInfo) {
+		client.logger.Infof("Active node is now %s", client.ActiveURL())
+	}
+}
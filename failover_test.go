@@ -0,0 +1,182 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyNode answers chain id requests while up and drops the connection while down.
+func flakyNode(t *testing.T, chainID int, up *atomic.Bool) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up.Load() {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		fmt.Fprintf(w, `{"chain_id":%d}`, chainID)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithFallbackURLs(t *testing.T) {
+	var primaryUp, fallbackUp atomic.Bool
+	primaryUp.Store(true)
+	fallbackUp.Store(true)
+	primary := flakyNode(t, 1, &primaryUp)
+	fallback := flakyNode(t, 2, &fallbackUp)
+
+	client := newClientInternal(primary.URL, WithTimeout(time.Second), WithFallbackURLs(fallback.URL))
+	client.failover.probeInterval = 20 * time.Millisecond
+	ctx := context.Background()
+
+	chainID := func() int {
+		t.Helper()
+		result, err := client.GetChainId(ctx)
+		if err != nil {
+			t.Fatalf("GetChainId failed: %v", err)
+		}
+		return result.ChainId
+	}
+
+	if got := chainID(); got != 1 || client.ActiveURL() != primary.URL {
+		t.Fatalf("expected primary to serve, got chain %d from %s", got, client.ActiveURL())
+	}
+
+	primaryUp.Store(false)
+	if got := chainID(); got != 2 {
+		t.Errorf("expected failover to the fallback, got chain %d", got)
+	}
+	if client.ActiveURL() != fallback.URL {
+		t.Errorf("ActiveURL = %s, want %s", client.ActiveURL(), fallback.URL)
+	}
+
+	// The primary is not probed again until the interval has passed.
+	primaryUp.Store(true)
+	if got := chainID(); got != 2 {
+		t.Errorf("expected fallback before the probe interval, got chain %d", got)
+	}
+	time.Sleep(30 * time.Millisecond)
+	// This request starts the probe in the background without waiting for it.
+	chainID()
+	deadline := time.Now().Add(time.Second)
+	for client.ActiveURL() != primary.URL && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := chainID(); got != 1 || client.ActiveURL() != primary.URL {
+		t.Errorf("expected switch back to the recovered primary, got chain %d from %s", got, client.ActiveURL())
+	}
+
+	primaryUp.Store(false)
+	fallbackUp.Store(false)
+	var netErr *NetworkError
+	if _, err := client.GetChainId(ctx); !errors.As(err, &netErr) {
+		t.Errorf("expected a NetworkError with every node down, got %v", err)
+	}
+}
+
+func TestWithFallbackURLs_APIErrorDoesNotFailOver(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"error_code":"INVALID_PARAMETER","message":"bad"}`)
+	}))
+	defer primary.Close()
+	fallbackHits := 0
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackHits++
+		fmt.Fprintln(w, `{"chain_id":2}`)
+	}))
+	defer fallback.Close()
+
+	client := newClientInternal(primary.URL, WithTimeout(time.Second), WithFallbackURLs(fallback.URL))
+	if _, err := client.GetChainId(context.Background()); !IsErrorCode(err, "INVALID_PARAMETER") {
+		t.Errorf("expected the primary's API error, got %v", err)
+	}
+	if fallbackHits != 0 || client.ActiveURL() != primary.URL {
+		t.Errorf("API error should not fail over (fallback hits %d, active %s)", fallbackHits, client.ActiveURL())
+	}
+}
+
+func TestWithFallbackURLs_POST(t *testing.T) {
+	var fallbackHits atomic.Int32
+	fallback := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fallbackHits.Add(1)
+		fmt.Fprintln(w, `{"hash":"0xabc"}`)
+	}))
+	defer fallback.Close()
+	var result PaymentResponse
+
+	// The request reached the primary before the connection dropped, so it
+	// must not be sent again.
+	var down atomic.Bool
+	dropped := flakyNode(t, 1, &down)
+	client := newClientInternal(dropped.URL, WithTimeout(time.Second), WithFallbackURLs(fallback.URL))
+	var netErr *NetworkError
+	if err := client.PostMethod(context.Background(), "/v1/transactions/payment", struct{}{}, &result); !errors.As(err, &netErr) {
+		t.Errorf("expected the primary's NetworkError, got %v", err)
+	}
+	if fallbackHits.Load() != 0 {
+		t.Errorf("POST was re-sent to the fallback after a dropped connection")
+	}
+
+	// A refused connection never reached the node, so failing over is safe.
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+	client = newClientInternal(refused.URL, WithTimeout(time.Second), WithFallbackURLs(fallback.URL))
+	if err := client.PostMethod(context.Background(), "/v1/transactions/payment", struct{}{}, &result); err != nil {
+		t.Errorf("expected the POST to fail over after a refused connection, got %v", err)
+	}
+	if fallbackHits.Load() != 1 {
+		t.Errorf("fallback hits = %d, want 1", fallbackHits.Load())
+	}
+}
+
+func TestWithFallbackURLs_RetryBudget(t *testing.T) {
+	refused := httptest.NewServer(http.NotFoundHandler())
+	refused.Close()
+	fallback := flakyNode(t, 2, new(atomic.Bool))
+	client := newClientInternal(refused.URL, WithTimeout(time.Second), WithFallbackURLs(fallback.URL),
+		WithRetryBudget(NewRetryBudget(0)))
+	if _, err := client.GetChainId(context.Background()); !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Errorf("expected ErrRetryBudgetExhausted with an empty budget, got %v", err)
+	}
+}
+
+func TestWithFallbackURLs_ProbeInBackground(t *testing.T) {
+	release := make(chan struct{})
+	slowPrimary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		fmt.Fprintln(w, `{"chain_id":1}`)
+	}))
+	defer slowPrimary.Close()
+	defer close(release)
+	up := new(atomic.Bool)
+	up.Store(true)
+	fallback := flakyNode(t, 2, up)
+
+	hook := newMockHook(t)
+	client := newClientInternal(slowPrimary.URL, WithTimeout(time.Second), WithFallbackURLs(fallback.URL), WithHooks(hook))
+	client.failover.active = 1
+
+	start := time.Now()
+	if _, err := client.GetChainId(context.Background()); err != nil {
+		t.Fatalf("GetChainId failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("request waited %v for the primary probe", elapsed)
+	}
+	for _, call := range hook.getPreRequestCalls() {
+		if call.url != fallback.URL+"/v1/chains/chain_id" {
+			t.Errorf("probe fired a hook for %s", call.url)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+// Package fakenode provides an in-memory 1Money node for hermetic tests.
+//
+// A Node serves the account, payment, receipt, checkpoint and token endpoints
+// used by the SDK from an httptest.Server. Signatures are verified and the
+// sender is recovered from them, nonces must be used in order, and balances
+// change on mint, burn and payment. Receipts become visible after a
+// configurable delay, so receipt pollers see the same "not found, then found"
+// sequence as against a real node.
+package fakenode
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	onemoney "github.com/1Money-Co/1money-protocol-go-sdk"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DefaultChainID is the chain id served by a Node unless WithChainID is used.
+const DefaultChainID = 1212101
+
+// Option configures a Node.
+type Option func(*Node)
+
+// WithReceiptDelay sets how long after submission a transaction's receipt
+// becomes available. The default is zero.
+func WithReceiptDelay(d time.Duration) Option {
+	return func(n *Node) {
+		n.receiptDelay = d
+	}
+}
+
+// WithChainID sets the chain id the node reports and requires on transactions.
+func WithChainID(chainID uint64) Option {
+	return func(n *Node) {
+		n.chainID = chainID
+	}
+}
+
+type token struct {
+	symbol   string
+	decimals uint8
+	master   common.Address
+	supply   *big.Int
+	balances map[common.Address]*big.Int
+}
+
+type pendingReceipt struct {
+	receipt onemoney.TransactionReceiptResponse
+	readyAt time.Time
+}
+
+// Node is an in-memory node backed by an httptest.Server.
+type Node struct {
+	server       *httptest.Server
+	chainID      uint64
+	receiptDelay time.Duration
+
+	mu         sync.Mutex
+	checkpoint uint64
+	nonces     map[common.Address]uint64
+	tokens     map[common.Address]*token
+	receipts   map[string]*pendingReceipt
+	now        func() time.Time
+}
+
+// New starts a Node. Call Close when done.
+func New(opts ...Option) *Node {
+	n := &Node{
+		chainID:    DefaultChainID,
+		checkpoint: 1,
+		nonces:     make(map[common.Address]uint64),
+		tokens:     make(map[common.Address]*token),
+		receipts:   make(map[string]*pendingReceipt),
+		now:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/chains/chain_id", n.handleChainID)
+	mux.HandleFunc("GET /v1/checkpoints/number", n.handleCheckpointNumber)
+	mux.HandleFunc("GET /v1/accounts/nonce", n.handleNonce)
+	mux.HandleFunc("GET /v1/accounts/token_account", n.handleTokenAccount)
+	mux.HandleFunc("GET /v1/tokens/token_metadata", n.handleTokenMetadata)
+	mux.HandleFunc("GET /v1/transactions/receipt/by_hash", n.handleReceipt)
+	mux.HandleFunc("POST /v1/transactions/payment", n.handlePayment)
+	mux.HandleFunc("POST /v1/tokens/mint", n.handleMint)
+	mux.HandleFunc("POST /v1/tokens/burn", n.handleBurn)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, onemoney.ErrCodeNotFound, "endpoint not found")
+	})
+	n.server = httptest.NewServer(mux)
+	return n
+}
+
+// URL returns the base URL of the node.
+func (n *Node) URL() string {
+	return n.server.URL
+}
+
+// Close shuts the server down.
+func (n *Node) Close() {
+	n.server.Close()
+}
+
+// Client returns an SDK client whose requests are all routed to this node.
+// opts are applied after the routing option, so they must not replace the
+// HTTP client.
+func (n *Node) Client(opts ...onemoney.ClientOption) *onemoney.Client {
+	target, _ := url.Parse(n.server.URL)
+	httpClient := &http.Client{
+		Timeout:   4 * time.Second,
+		Transport: &rewriteTransport{target: target, base: n.server.Client().Transport},
+	}
+	return onemoney.NewClientWithOpts(append([]onemoney.ClientOption{onemoney.WithHTTPClient(httpClient)}, opts...)...)
+}
+
+type rewriteTransport struct {
+	target *url.URL
+	base   http.RoundTripper
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	req.Host = t.target.Host
+	return t.base.RoundTrip(req)
+}
+
+// CreateToken registers a token with the given master authority, which is the
+// only address allowed to mint and burn it, and returns its address.
+func (n *Node) CreateToken(symbol string, decimals uint8, master common.Address) common.Address {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	addr := common.BytesToAddress(crypto.Keccak256([]byte("fakenode-token"), []byte(symbol)))
+	n.tokens[addr] = &token{
+		symbol:   symbol,
+		decimals: decimals,
+		master:   master,
+		supply:   new(big.Int),
+		balances: make(map[common.Address]*big.Int),
+	}
+	return addr
+}
+
+// SetBalance sets holder's balance of tok directly, adjusting the supply.
+func (n *Node) SetBalance(tok, holder common.Address, amount *big.Int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	t, ok := n.tokens[tok]
+	if !ok {
+		return fmt.Errorf("fakenode: unknown token %s", tok.Hex())
+	}
+	t.supply.Sub(t.supply, t.balance(holder))
+	t.supply.Add(t.supply, amount)
+	t.balances[holder] = new(big.Int).Set(amount)
+	return nil
+}
+
+// Balance returns holder's balance of tok.
+func (n *Node) Balance(tok, holder common.Address) *big.Int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	t, ok := n.tokens[tok]
+	if !ok {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(t.balance(holder))
+}
+
+// Nonce returns the next nonce the node expects from addr.
+func (n *Node) Nonce(addr common.Address) uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.nonces[addr]
+}
+
+func (t *token) balance(holder common.Address) *big.Int {
+	if b, ok := t.balances[holder]; ok {
+		return b
+	}
+	return new(big.Int)
+}
+
+func (n *Node) handleChainID(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, onemoney.ChainIdResponse{ChainId: int(n.chainID)})
+}
+
+func (n *Node) handleCheckpointNumber(w http.ResponseWriter, r *http.Request) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	writeJSON(w, onemoney.CheckpointNumber{Number: int(n.checkpoint)})
+}
+
+func (n *Node) handleNonce(w http.ResponseWriter, r *http.Request) {
+	addr, ok := addressParam(w, r, "address")
+	if !ok {
+		return
+	}
+	writeJSON(w, onemoney.AccountNonceResponse{Nonce: n.Nonce(addr)})
+}
+
+func (n *Node) handleTokenAccount(w http.ResponseWriter, r *http.Request) {
+	holder, ok := addressParam(w, r, "address")
+	if !ok {
+		return
+	}
+	tok, ok := addressParam(w, r, "token")
+	if !ok {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	t, exists := n.tokens[tok]
+	if !exists {
+		writeError(w, http.StatusNotFound, onemoney.ErrCodeTokenNotFound, "token not found")
+		return
+	}
+	writeJSON(w, onemoney.TokenAccountResponse{
+		Balance:             t.balance(holder).String(),
+		Nonce:               int(n.nonces[holder]),
+		TokenAccountAddress: common.BytesToAddress(crypto.Keccak256(holder.Bytes(), tok.Bytes())).Hex(),
+	})
+}
+
+func (n *Node) handleTokenMetadata(w http.ResponseWriter, r *http.Request) {
+	tok, ok := addressParam(w, r, "token")
+	if !ok {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	t, exists := n.tokens[tok]
+	if !exists {
+		writeError(w, http.StatusNotFound, onemoney.ErrCodeTokenNotFound, "token not found")
+		return
+	}
+	writeJSON(w, onemoney.TokenInfoResponse{
+		Symbol:                  t.symbol,
+		MasterAuthority:         t.master.Hex(),
+		MasterMintBurnAuthority: t.master.Hex(),
+		Supply:                  t.supply.String(),
+		Decimals:                t.decimals,
+	})
+}
+
+func (n *Node) handleReceipt(w http.ResponseWriter, r *http.Request) {
+	hash := r.URL.Query().Get("hash")
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	p, ok := n.receipts[strings.ToLower(hash)]
+	if !ok || n.now().Before(p.readyAt) {
+		writeError(w, http.StatusNotFound, onemoney.ErrCodeTransactionNotFound, "transaction not found")
+		return
+	}
+	writeJSON(w, p.receipt)
+}
+
+func (n *Node) handlePayment(w http.ResponseWriter, r *http.Request) {
+	var req onemoney.PaymentRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	n.apply(w, req.PaymentPayload, req.Signature, req.ChainID, req.Nonce, req.Token, req.Recipient,
+		func(t *token, from common.Address) (int, string, string) {
+			value := req.Value.Int()
+			if value == nil || value.Sign() < 0 {
+				return http.StatusBadRequest, onemoney.ErrCodeBadInput, "invalid value"
+			}
+			if t.balance(from).Cmp(value) < 0 {
+				return http.StatusBadRequest, onemoney.ErrCodeInsufficientBalance, "insufficient balance"
+			}
+			t.balances[from] = new(big.Int).Sub(t.balance(from), value)
+			t.balances[req.Recipient] = new(big.Int).Add(t.balance(req.Recipient), value)
+			return 0, "", ""
+		})
+}
+
+func (n *Node) handleMint(w http.ResponseWriter, r *http.Request) {
+	var req onemoney.MintTokenRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	n.apply(w, req.TokenMintPayload, req.Signature, req.ChainID, req.Nonce, req.Token, req.Recipient,
+		func(t *token, from common.Address) (int, string, string) {
+			if from != t.master {
+				return http.StatusBadRequest, onemoney.ErrCodeUnauthorized, "signer is not a mint authority"
+			}
+			value := req.Value.Int()
+			if value == nil || value.Sign() < 0 {
+				return http.StatusBadRequest, onemoney.ErrCodeBadInput, "invalid value"
+			}
+			t.balances[req.Recipient] = new(big.Int).Add(t.balance(req.Recipient), value)
+			t.supply.Add(t.supply, value)
+			return 0, "", ""
+		})
+}
+
+func (n *Node) handleBurn(w http.ResponseWriter, r *http.Request) {
+	var req onemoney.BurnTokenRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+	n.apply(w, req.TokenBurnPayload, req.Signature, req.ChainID, req.Nonce, req.Token, req.Recipient,
+		func(t *token, from common.Address) (int, string, string) {
+			if from != t.master {
+				return http.StatusBadRequest, onemoney.ErrCodeUnauthorized, "signer is not a burn authority"
+			}
+			value := req.Value.Int()
+			if value == nil || value.Sign() < 0 {
+				return http.StatusBadRequest, onemoney.ErrCodeBadInput, "invalid value"
+			}
+			if t.balance(req.Recipient).Cmp(value) < 0 {
+				return http.StatusBadRequest, onemoney.ErrCodeInsufficientBalance, "insufficient balance"
+			}
+			t.balances[req.Recipient] = new(big.Int).Sub(t.balance(req.Recipient), value)
+			t.supply.Sub(t.supply, value)
+			return 0, "", ""
+		})
+}
+
+// apply performs the checks shared by every signed transaction, runs mutate
+// under the node lock and records a receipt.
+func (n *Node) apply(w http.ResponseWriter, payload interface{}, sig onemoney.Signature, chainID, nonce uint64,
+	tok, to common.Address, mutate func(t *token, from common.Address) (int, string, string)) {
+	from, digest, err := recoverSender(payload, sig)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, onemoney.ErrCodeInvalidSignature, err.Error())
+		return
+	}
+	if chainID != n.chainID {
+		writeError(w, http.StatusBadRequest, onemoney.ErrCodeInvalidChainID, fmt.Sprintf("expected chain id %d", n.chainID))
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if expected := n.nonces[from]; nonce != expected {
+		writeError(w, http.StatusBadRequest, onemoney.ErrCodeInvalidNonce, fmt.Sprintf("expected nonce %d, got %d", expected, nonce))
+		return
+	}
+	t, ok := n.tokens[tok]
+	if !ok {
+		writeError(w, http.StatusNotFound, onemoney.ErrCodeTokenNotFound, "token not found")
+		return
+	}
+	if status, code, msg := mutate(t, from); status != 0 {
+		writeError(w, status, code, msg)
+		return
+	}
+
+	n.nonces[from]++
+	n.checkpoint++
+	hash := common.BytesToHash(crypto.Keccak256(digest, []byte(sig.R), []byte(sig.S))).Hex()
+	n.receipts[strings.ToLower(hash)] = &pendingReceipt{
+		receipt: onemoney.TransactionReceiptResponse{
+			CheckpointHash:   common.BigToHash(new(big.Int).SetUint64(n.checkpoint)).Hex(),
+			CheckpointNumber: int(n.checkpoint),
+			From:             from.Hex(),
+			Success:          true,
+			To:               to.Hex(),
+			TokenAddress:     tok.Hex(),
+			TransactionHash:  hash,
+		},
+		readyAt: n.now().Add(n.receiptDelay),
+	}
+	writeJSON(w, onemoney.PaymentResponse{Hash: hash})
+}
+
+func recoverSender(payload interface{}, sig onemoney.Signature) (common.Address, []byte, error) {
+	encoded, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("encode payload: %w", err)
+	}
+	digest := crypto.Keccak256(encoded)
+	raw := make([]byte, 0, 65)
+	raw = append(raw, common.HexToHash(sig.R).Bytes()...)
+	raw = append(raw, common.HexToHash(sig.S).Bytes()...)
+	raw = append(raw, byte(sig.V))
+	pub, err := crypto.SigToPub(digest, raw)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), digest, nil
+}
+
+func addressParam(w http.ResponseWriter, r *http.Request, name string) (common.Address, bool) {
+	v := r.URL.Query().Get(name)
+	if !common.IsHexAddress(v) {
+		writeError(w, http.StatusBadRequest, onemoney.ErrCodeBadInput, fmt.Sprintf("invalid %s", name))
+		return common.Address{}, false
+	}
+	return common.HexToAddress(v), true
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, onemoney.ErrCodeBadInput, err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(onemoney.ErrorResponse{ErrorCode: code, Message: message})
+}
@@ -0,0 +1,135 @@
+package fakenode
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	onemoney "github.com/1Money-Co/1money-protocol-go-sdk"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func newKey(t *testing.T) (string, common.Address) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return common.Bytes2Hex(crypto.FromECDSA(key)), crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestNode_MintPayAndReceipt(t *testing.T) {
+	node := New(WithReceiptDelay(50 * time.Millisecond))
+	defer node.Close()
+	client := node.Client()
+	ctx := context.Background()
+	fastWait := onemoney.WaitOpts{Interval: 10 * time.Millisecond, MaxInterval: 20 * time.Millisecond, Multiplier: 1, MaxRetries: 50}
+
+	masterKey, master := newKey(t)
+	_, recipient := newKey(t)
+	token := node.CreateToken("FAKE", 6, master)
+
+	cp, err := client.GetCheckpointNumber(ctx)
+	if err != nil {
+		t.Fatalf("GetCheckpointNumber failed: %v", err)
+	}
+	mint := onemoney.TokenMintPayload{
+		RecentCheckpoint: uint64(cp.Number),
+		ChainID:          DefaultChainID,
+		Nonce:            0,
+		Recipient:        master,
+		Value:            onemoney.NewTokenValue(big.NewInt(1000)),
+		Token:            token,
+	}
+	sig, err := client.SignMessage(mint, masterKey)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	mintResp, err := client.MintToken(ctx, &onemoney.MintTokenRequest{TokenMintPayload: mint, Signature: *sig})
+	if err != nil {
+		t.Fatalf("MintToken failed: %v", err)
+	}
+
+	// The receipt is delayed, so the first lookup misses.
+	if _, err := client.GetTransactionReceipt(ctx, mintResp.Hash); !onemoney.IsTransactionNotFound(err) {
+		t.Fatalf("expected TRANSACTION_NOT_FOUND before the delay, got %v", err)
+	}
+	receipt, err := client.WaitForReceipt(ctx, mintResp.Hash, fastWait)
+	if err != nil {
+		t.Fatalf("WaitForReceipt failed: %v", err)
+	}
+	if !receipt.Success || receipt.From != master.Hex() {
+		t.Errorf("unexpected receipt: %+v", receipt)
+	}
+
+	nonce, err := client.GetAccountNonce(ctx, master.Hex())
+	if err != nil || nonce.Nonce != 1 {
+		t.Fatalf("GetAccountNonce = %+v, %v; want nonce 1", nonce, err)
+	}
+
+	payment := onemoney.PaymentPayload{
+		RecentCheckpoint: uint64(cp.Number),
+		ChainID:          DefaultChainID,
+		Nonce:            1,
+		Recipient:        recipient,
+		Value:            onemoney.NewTokenValue(big.NewInt(400)),
+		Token:            token,
+	}
+	sig, err = client.SignMessage(payment, masterKey)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	if _, err := client.SendPayment(ctx, &onemoney.PaymentRequest{PaymentPayload: payment, Signature: *sig}); err != nil {
+		t.Fatalf("SendPayment failed: %v", err)
+	}
+
+	if got := node.Balance(token, master); got.Cmp(big.NewInt(600)) != 0 {
+		t.Errorf("master balance = %s, want 600", got)
+	}
+	account, err := client.GetTokenAccount(ctx, recipient.Hex(), token.Hex())
+	if err != nil {
+		t.Fatalf("GetTokenAccount failed: %v", err)
+	}
+	if account.Balance != "400" {
+		t.Errorf("recipient balance = %s, want 400", account.Balance)
+	}
+
+	// Replaying the same nonce is rejected.
+	_, err = client.SendPayment(ctx, &onemoney.PaymentRequest{PaymentPayload: payment, Signature: *sig})
+	if !onemoney.IsNonceConflict(err) {
+		t.Errorf("expected nonce error on replay, got %v", err)
+	}
+}
+
+func TestNode_Rejections(t *testing.T) {
+	node := New()
+	defer node.Close()
+	client := node.Client()
+	ctx := context.Background()
+
+	_, master := newKey(t)
+	otherKey, other := newKey(t)
+	token := node.CreateToken("FAKE", 6, master)
+	if err := node.SetBalance(token, other, big.NewInt(5)); err != nil {
+		t.Fatalf("SetBalance failed: %v", err)
+	}
+
+	mint := onemoney.TokenMintPayload{ChainID: DefaultChainID, Recipient: other, Value: onemoney.NewTokenValue(big.NewInt(1)), Token: token}
+	sig, _ := client.SignMessage(mint, otherKey)
+	_, err := client.MintToken(ctx, &onemoney.MintTokenRequest{TokenMintPayload: mint, Signature: *sig})
+	if !onemoney.IsErrorCode(err, onemoney.ErrCodeUnauthorized) {
+		t.Errorf("expected UNAUTHORIZED for non-authority mint, got %v", err)
+	}
+
+	payment := onemoney.PaymentPayload{ChainID: DefaultChainID, Recipient: master, Value: onemoney.NewTokenValue(big.NewInt(10)), Token: token}
+	sig, _ = client.SignMessage(payment, otherKey)
+	_, err = client.SendPayment(ctx, &onemoney.PaymentRequest{PaymentPayload: payment, Signature: *sig})
+	if !onemoney.IsInsufficientBalance(err) {
+		t.Errorf("expected INSUFFICIENT_BALANCE, got %v", err)
+	}
+	if node.Nonce(other) != 0 {
+		t.Errorf("rejected transactions must not consume a nonce")
+	}
+}
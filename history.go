@@ -0,0 +1,114 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// HistoryOptions selects one page of a history endpoint. Zero fields are left
+// out of the query, so the node applies its defaults.
+type HistoryOptions struct {
+	// Limit is the maximum number of events per page.
+	Limit int
+	// Cursor continues from the NextCursor of a previous page.
+	Cursor string
+	// FromCheckpoint and ToCheckpoint bound the events by checkpoint, inclusive.
+	FromCheckpoint uint64
+	ToCheckpoint   uint64
+}
+
+func (opts HistoryOptions) values() url.Values {
+	params := url.Values{}
+	if opts.Limit > 0 {
+		params.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Cursor != "" {
+		params.Set("cursor", opts.Cursor)
+	}
+	if opts.FromCheckpoint > 0 {
+		params.Set("from_checkpoint", strconv.FormatUint(opts.FromCheckpoint, 10))
+	}
+	if opts.ToCheckpoint > 0 {
+		params.Set("to_checkpoint", strconv.FormatUint(opts.ToCheckpoint, 10))
+	}
+	return params
+}
+
+// BurnEvent records one burn of a token.
+type BurnEvent struct {
+	TxHash        string `json:"tx_hash"`
+	BurnAuthority string `json:"burn_authority"`
+	BurnedFrom    string `json:"burned_from"`
+	Amount        string `json:"amount"`
+	Checkpoint    uint64 `json:"checkpoint"`
+	Nonce         uint64 `json:"nonce"`
+}
+
+// BurnHistoryPage is one page of GetTokenBurnHistory. NextCursor is empty on
+// the last page.
+type BurnHistoryPage struct {
+	Events     []BurnEvent `json:"events"`
+	NextCursor string      `json:"next_cursor"`
+}
+
+// GetTokenBurnHistory returns one page of the token's burns, oldest first.
+func (client *Client) GetTokenBurnHistory(ctx context.Context, tokenAddress string, opts HistoryOptions) (*BurnHistoryPage, error) {
+	result := new(BurnHistoryPage)
+	params := opts.values()
+	params.Set("token", tokenAddress)
+	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/tokens/burn_history?%s", params.Encode()), result)
+}
+
+// BurnHistoryIterator walks every page of a token's burn history. Call Next
+// until it returns false, reading each event with Event, then check Err.
+type BurnHistoryIterator struct {
+	client *Client
+	token  string
+	opts   HistoryOptions
+
+	page []BurnEvent
+	pos  int
+	done bool
+	err  error
+}
+
+// NewBurnHistoryIterator returns an iterator over the token's burns, starting at
+// opts.Cursor and fetching opts.Limit events per page.
+func NewBurnHistoryIterator(client *Client, tokenAddress string, opts HistoryOptions) *BurnHistoryIterator {
+	return &BurnHistoryIterator{client: client, token: tokenAddress, opts: opts, pos: -1}
+}
+
+// Next advances to the next event, fetching the next page when needed. It
+// returns false at the end of the history or on error; check Err.
+func (it *BurnHistoryIterator) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+	it.pos++
+	for it.pos >= len(it.page) {
+		if it.done {
+			return false
+		}
+		page, err := it.client.GetTokenBurnHistory(ctx, it.token, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page, it.pos = page.Events, 0
+		it.opts.Cursor = page.NextCursor
+		it.done = page.NextCursor == ""
+	}
+	return true
+}
+
+// Event returns the current event. It is only valid after Next returned true.
+func (it *BurnHistoryIterator) Event() BurnEvent {
+	return it.page[it.pos]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *BurnHistoryIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,93 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func burnHistoryServer(t *testing.T, queries *[]string) *httptest.Server {
+	t.Helper()
+	pages := map[string]string{
+		"": `{"events":[
+			{"tx_hash":"0x01","burn_authority":"0xaa","burned_from":"0xbb","amount":"100","checkpoint":10,"nonce":1},
+			{"tx_hash":"0x02","burn_authority":"0xaa","burned_from":"0xcc","amount":"250","checkpoint":12,"nonce":2}
+		],"next_cursor":"page2"}`,
+		"page2": `{"events":[],"next_cursor":"page3"}`,
+		"page3": `{"events":[
+			{"tx_hash":"0x03","burn_authority":"0xaa","burned_from":"0xbb","amount":"5","checkpoint":20,"nonce":3}
+		],"next_cursor":""}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/burn_history" || r.URL.Query().Get("token") != "0xtoken" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		*queries = append(*queries, r.URL.RawQuery)
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, `{"error_code":"BAD_INPUT","message":"unknown cursor"}`)
+			return
+		}
+		fmt.Fprintln(w, page)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetTokenBurnHistory(t *testing.T) {
+	var queries []string
+	server := burnHistoryServer(t, &queries)
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+
+	page, err := client.GetTokenBurnHistory(context.Background(), "0xtoken", HistoryOptions{Limit: 2, FromCheckpoint: 5})
+	if err != nil {
+		t.Fatalf("GetTokenBurnHistory failed: %v", err)
+	}
+	if len(page.Events) != 2 || page.NextCursor != "page2" {
+		t.Fatalf("Unexpected page: %+v", page)
+	}
+	want := BurnEvent{TxHash: "0x02", BurnAuthority: "0xaa", BurnedFrom: "0xcc", Amount: "250", Checkpoint: 12, Nonce: 2}
+	if page.Events[1] != want {
+		t.Errorf("Events[1] = %+v, want %+v", page.Events[1], want)
+	}
+	if queries[0] != "from_checkpoint=5&limit=2&token=0xtoken" {
+		t.Errorf("Unexpected query: %s", queries[0])
+	}
+}
+
+func TestBurnHistoryIterator(t *testing.T) {
+	var queries []string
+	server := burnHistoryServer(t, &queries)
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+
+	it := NewBurnHistoryIterator(client, "0xtoken", HistoryOptions{Limit: 2})
+	var hashes []string
+	for it.Next(context.Background()) {
+		hashes = append(hashes, it.Event().TxHash)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Iteration failed: %v", err)
+	}
+	if fmt.Sprint(hashes) != "[0x01 0x02 0x03]" {
+		t.Errorf("Iterated %v", hashes)
+	}
+	if len(queries) != 3 {
+		t.Errorf("Expected 3 page fetches including the empty page, got %d", len(queries))
+	}
+	if it.Next(context.Background()) || len(queries) != 3 {
+		t.Error("Next after the last page should not fetch again")
+	}
+
+	it = NewBurnHistoryIterator(client, "0xtoken", HistoryOptions{Cursor: "bogus"})
+	if it.Next(context.Background()) {
+		t.Error("Expected Next to fail on an API error")
+	}
+	if !IsErrorCode(it.Err(), ErrCodeBadInput) {
+		t.Errorf("Err = %v, want BAD_INPUT", it.Err())
+	}
+}
@@ -0,0 +1,48 @@
+package onemoney
+
+import (
+	"crypto/tls"
+	"net/http"
+)
+
+// WithHTTP2Enabled forces HTTP/2 on (force=true) or off (force=false) for the
+// client's transport. Go normally negotiates HTTP/2 over TLS on its own, but a
+// custom *http.Transport silently falls back to HTTP/1.1 unless ForceAttemptHTTP2
+// is set. Apply it after WithHTTPClient; transports that are not *http.Transport
+// are left unchanged.
+func WithHTTP2Enabled(force bool) ClientOption {
+	return func(c *Client) {
+		var transport *http.Transport
+		switch rt := c.httpclient.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = rt.Clone()
+		default:
+			return
+		}
+		if force {
+			transport.ForceAttemptHTTP2 = true
+			transport.TLSNextProto = nil
+		} else {
+			transport.ForceAttemptHTTP2 = false
+			transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+			if transport.TLSClientConfig != nil {
+				// Stop advertising h2 over ALPN so the server does not pick it.
+				var protos []string
+				for _, proto := range transport.TLSClientConfig.NextProtos {
+					if proto != "h2" {
+						protos = append(protos, proto)
+					}
+				}
+				transport.TLSClientConfig.NextProtos = protos
+			}
+		}
+		c.httpclient.Transport = transport
+	}
+}
+
+// IsHTTP2 reports whether resp was served over HTTP/2.
+func IsHTTP2(resp *http.Response) bool {
+	return resp != nil && resp.ProtoMajor == 2
+}
@@ -0,0 +1,47 @@
+package onemoney
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithHTTP2Enabled(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"status":"ok"}`)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		force     bool
+		wantHTTP2 bool
+	}{
+		{"forced on", true, true},
+		{"forced off", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// server.Client() trusts the test certificate but does not itself force HTTP/2.
+			client := newClientInternal(server.URL, WithHTTPClient(server.Client()), WithHTTP2Enabled(tt.force))
+			resp, err := client.httpclient.Get(server.URL)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			resp.Body.Close()
+			if IsHTTP2(resp) != tt.wantHTTP2 {
+				t.Errorf("IsHTTP2 = %v (proto %s), want %v", IsHTTP2(resp), resp.Proto, tt.wantHTTP2)
+			}
+		})
+	}
+}
+
+func TestIsHTTP2_Nil(t *testing.T) {
+	if IsHTTP2(nil) {
+		t.Error("Expected IsHTTP2(nil) to be false")
+	}
+}
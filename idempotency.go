@@ -0,0 +1,54 @@
+package onemoney
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+)
+
+type idempotencyKeyCtxKey struct{}
+
+// idempotencyNamespace is the RFC 4122 URL namespace, used as the namespace for
+// UUIDv5 idempotency keys.
+var idempotencyNamespace = [16]byte{
+	0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1,
+	0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8,
+}
+
+// WithIdempotencyKey returns a copy of ctx carrying key. PostMethod sends it as
+// the Idempotency-Key header so a retried submission is not applied twice by
+// nodes that honour the header.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the key set by WithIdempotencyKey, if any.
+func IdempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyCtxKey{}).(string)
+	return key, ok && key != ""
+}
+
+// GenerateIdempotencyKey derives a deterministic UUIDv5 from payloadHash, so
+// the same signed payload always maps to the same key.
+func GenerateIdempotencyKey(payloadHash []byte) string {
+	h := sha1.New()
+	h.Write(idempotencyNamespace[:])
+	h.Write(payloadHash)
+	sum := h.Sum(nil)
+
+	var u [16]byte
+	copy(u[:], sum[:16])
+	u[6] = (u[6] & 0x0f) | 0x50 // version 5
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}
+
+// PayloadIdempotencyKey returns the idempotency key for a transaction payload,
+// derived from the same digest that is signed.
+func PayloadIdempotencyKey(payload interface{}) (string, error) {
+	digest, err := messageDigest(payload)
+	if err != nil {
+		return "", err
+	}
+	return GenerateIdempotencyKey(digest), nil
+}
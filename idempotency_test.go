@@ -0,0 +1,68 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGenerateIdempotencyKey(t *testing.T) {
+	// Matches Python's uuid.uuid5(uuid.NAMESPACE_URL, "abc").
+	if got, want := GenerateIdempotencyKey([]byte("abc")), "68661508-f3c4-55b4-945d-ae2b4dfe5db4"; got != want {
+		t.Errorf("GenerateIdempotencyKey = %s, want %s", got, want)
+	}
+
+	payload := PaymentPayload{
+		RecentCheckpoint: 10,
+		ChainID:          1212101,
+		Nonce:            3,
+		Recipient:        common.HexToAddress("0x2"),
+		Value:            NewTokenValue(big.NewInt(100)),
+		Token:            common.HexToAddress("0x3"),
+	}
+	k1, err := PayloadIdempotencyKey(payload)
+	if err != nil {
+		t.Fatalf("PayloadIdempotencyKey failed: %v", err)
+	}
+	k2, _ := PayloadIdempotencyKey(payload)
+	if k1 != k2 {
+		t.Errorf("key is not deterministic: %s != %s", k1, k2)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-5[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(k1) {
+		t.Errorf("key %q is not a UUIDv5", k1)
+	}
+	payload.Nonce++
+	if k3, _ := PayloadIdempotencyKey(payload); k3 == k1 {
+		t.Error("different payloads produced the same key")
+	}
+}
+
+func TestPostMethod_IdempotencyKeyHeader(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"hash":"0xabc"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	var result PaymentResponse
+	ctx := WithIdempotencyKey(context.Background(), "68661508-f3c4-55b4-945d-ae2b4dfe5db4")
+	if err := client.PostMethod(ctx, "/v1/transactions/payment", map[string]string{}, &result); err != nil {
+		t.Fatalf("PostMethod failed: %v", err)
+	}
+	if err := client.PostMethod(context.Background(), "/v1/transactions/payment", map[string]string{}, &result); err != nil {
+		t.Fatalf("PostMethod failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != "68661508-f3c4-55b4-945d-ae2b4dfe5db4" || got[1] != "" {
+		t.Errorf("Idempotency-Key headers = %q", got)
+	}
+}
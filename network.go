@@ -0,0 +1,206 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// feeScheduleCacheTTL is how long GetFeeSchedule reuses a fetched schedule.
+const feeScheduleCacheTTL = 5 * time.Minute
+
+// NodeInfo describes the software and network a node is running.
+type NodeInfo struct {
+	Version string `json:"version"`
+	ChainID uint64 `json:"chain_id"`
+	Network string `json:"network"`
+}
+
+// GetNodeInfo returns the version, chain id and network name reported by the node.
+func (client *Client) GetNodeInfo(ctx context.Context) (*NodeInfo, error) {
+	result := new(NodeInfo)
+	return result, client.GetMethod(ctx, "/v1/network/node_info", result)
+}
+
+// ValidatorInfo describes a validator participating in consensus.
+type ValidatorInfo struct {
+	Address        string  `json:"address"`
+	Stake          string  `json:"stake"`
+	Uptime         float64 `json:"uptime"`
+	LastCheckpoint uint64  `json:"last_checkpoint"`
+}
+
+// StakingInfo describes the stake held by an address.
+type StakingInfo struct {
+	Address     string `json:"address"`
+	Stake       string `json:"stake"`
+	Delegated   string `json:"delegated"`
+	Rewards     string `json:"rewards"`
+	IsValidator bool   `json:"is_validator"`
+}
+
+// GetValidatorList returns the validators currently known to the node.
+func (client *Client) GetValidatorList(ctx context.Context) ([]ValidatorInfo, error) {
+	var result []ValidatorInfo
+	if err := client.GetMethod(ctx, "/v1/network/validators", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetStakingInfo returns the staking position of address.
+func (client *Client) GetStakingInfo(ctx context.Context, address string) (*StakingInfo, error) {
+	result := new(StakingInfo)
+	params := url.Values{}
+	params.Set("address", address)
+	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/network/staking?%s", params.Encode()), result)
+}
+
+// FeeSchedule lists the base fee charged for each transaction type.
+type FeeSchedule struct {
+	PaymentFeeBase   string `json:"payment_fee_base"`
+	MintFeeBase      string `json:"mint_fee_base"`
+	BurnFeeBase      string `json:"burn_fee_base"`
+	IssueFeeBase     string `json:"issue_fee_base"`
+	AuthorityFeeBase string `json:"authority_fee_base"`
+	PauseFeeBase     string `json:"pause_fee_base"`
+}
+
+type feeScheduleCache struct {
+	mu        sync.Mutex
+	schedule  *FeeSchedule
+	fetchedAt time.Time
+	fetch     *sharedFetch[FeeSchedule] // in-flight refresh, if any
+}
+
+// GetFeeSchedule returns the network's fee structure. The result is cached on
+// the client for five minutes; concurrent misses share one fetch.
+func (client *Client) GetFeeSchedule(ctx context.Context) (*FeeSchedule, error) {
+	cache := &client.feeSchedule
+	cache.mu.Lock()
+	if cache.schedule != nil && time.Since(cache.fetchedAt) < feeScheduleCacheTTL {
+		schedule := *cache.schedule
+		cache.mu.Unlock()
+		return &schedule, nil
+	}
+	if cache.fetch == nil {
+		cache.fetch = startSharedFetch(ctx, func(ctx context.Context) (FeeSchedule, error) {
+			var result FeeSchedule
+			err := client.GetMethod(ctx, "/v1/network/fee_schedule", &result)
+			return result, err
+		}, func(schedule FeeSchedule, err error) {
+			cache.mu.Lock()
+			defer cache.mu.Unlock()
+			cache.fetch = nil
+			if err == nil {
+				cache.schedule = &schedule
+				cache.fetchedAt = time.Now()
+			}
+		})
+	}
+	fetch := cache.fetch
+	cache.mu.Unlock()
+
+	schedule, err := fetch.wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// PeerList is the set of peers a node is currently connected to.
+type PeerList struct {
+	Peers []string `json:"peers"`
+}
+
+// GetPeers returns the base URLs of the peers the node is connected to.
+func (client *Client) GetPeers(ctx context.Context) (*PeerList, error) {
+	result := new(PeerList)
+	return result, client.GetMethod(ctx, "/v1/network/peers", result)
+}
+
+// DiscoverPeers asks the node at seedURL for its peers and returns the seed
+// followed by every peer, with duplicates removed. opts configure the client
+// used for the lookup.
+func DiscoverPeers(ctx context.Context, seedURL string, opts ...ClientOption) ([]string, error) {
+	client := newClientInternal(strings.TrimRight(seedURL, "/"), opts...)
+	peers, err := client.GetPeers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("discover peers from %s: %w", seedURL, err)
+	}
+	return MergeNodeURLs([]string{seedURL}, peers.Peers), nil
+}
+
+// MergeNodeURLs concatenates the given URL lists, keeping the first occurrence
+// of each node. URLs are compared after trimming whitespace and trailing
+// slashes; empty entries are dropped.
+func MergeNodeURLs(lists ...[]string) []string {
+	seen := make(map[string]struct{})
+	var merged []string
+	for _, list := range lists {
+		for _, raw := range list {
+			u := strings.TrimRight(strings.TrimSpace(raw), "/")
+			if u == "" {
+				continue
+			}
+			if _, ok := seen[u]; ok {
+				continue
+			}
+			seen[u] = struct{}{}
+			merged = append(merged, u)
+		}
+	}
+	return merged
+}
+
+// ErrInsecureProductionNode is returned by ParseNodeURLs for a plain-HTTP URL
+// that does not point at the local machine, unless HTTP was explicitly allowed.
+var ErrInsecureProductionNode = errors.New("insecure http URL for non-local node")
+
+// ParseNodeURLs splits a comma- or whitespace-separated list of node URLs.
+// Entries without a scheme get https://. Explicit http:// URLs are accepted for
+// localhost and loopback addresses; for any other host they are rejected with
+// ErrInsecureProductionNode unless allowHTTP is set. Duplicates are removed as
+// in MergeNodeURLs.
+func ParseNodeURLs(s string, allowHTTP bool) ([]string, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	nodes := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if !strings.Contains(field, "://") {
+			field = "https://" + field
+		}
+		u, err := url.Parse(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node URL %q: %w", field, err)
+		}
+		if u.Host == "" {
+			return nil, fmt.Errorf("invalid node URL %q: missing host", field)
+		}
+		switch u.Scheme {
+		case "https":
+		case "http":
+			if !allowHTTP && !isLocalHost(u.Hostname()) {
+				return nil, fmt.Errorf("%w: %s", ErrInsecureProductionNode, field)
+			}
+		default:
+			return nil, fmt.Errorf("invalid node URL %q: unsupported scheme %q", field, u.Scheme)
+		}
+		nodes = append(nodes, field)
+	}
+	return MergeNodeURLs(nodes), nil
+}
+
+func isLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
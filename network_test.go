@@ -0,0 +1,244 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetNodeInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/network/node_info" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND", "message":"Endpoint not found"}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"version":"1.4.2","chain_id":1212101,"network":"testnet"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	result, err := client.GetNodeInfo(context.Background())
+	if err != nil {
+		t.Fatalf("GetNodeInfo failed: %v", err)
+	}
+	if result.Version != "1.4.2" {
+		t.Errorf("Expected version '1.4.2', got '%s'", result.Version)
+	}
+	if result.ChainID != 1212101 {
+		t.Errorf("Expected chain id 1212101, got %d", result.ChainID)
+	}
+	if result.Network != "testnet" {
+		t.Errorf("Expected network 'testnet', got '%s'", result.Network)
+	}
+}
+
+func TestGetValidatorList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/network/validators" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `[
+			{"address":"0x01","stake":"1000","uptime":99.5,"last_checkpoint":1200},
+			{"address":"0x02","stake":"2500","uptime":87.25,"last_checkpoint":1180}
+		]`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	validators, err := client.GetValidatorList(context.Background())
+	if err != nil {
+		t.Fatalf("GetValidatorList failed: %v", err)
+	}
+	if len(validators) != 2 {
+		t.Fatalf("Expected 2 validators, got %d", len(validators))
+	}
+	want := ValidatorInfo{Address: "0x02", Stake: "2500", Uptime: 87.25, LastCheckpoint: 1180}
+	if validators[1] != want {
+		t.Errorf("Expected %+v, got %+v", want, validators[1])
+	}
+}
+
+func TestGetStakingInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/network/staking" || r.URL.Query().Get("address") != "0x01" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"address":"0x01","stake":"1000","delegated":"250","rewards":"12","is_validator":true}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	info, err := client.GetStakingInfo(context.Background(), "0x01")
+	if err != nil {
+		t.Fatalf("GetStakingInfo failed: %v", err)
+	}
+	want := StakingInfo{Address: "0x01", Stake: "1000", Delegated: "250", Rewards: "12", IsValidator: true}
+	if *info != want {
+		t.Errorf("Expected %+v, got %+v", want, *info)
+	}
+}
+
+func TestGetFeeSchedule_Cache(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/network/fee_schedule" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"payment_fee_base":"10","mint_fee_base":"20","burn_fee_base":"20","issue_fee_base":"1000","authority_fee_base":"50","pause_fee_base":"50"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	for i := 0; i < 3; i++ {
+		schedule, err := client.GetFeeSchedule(context.Background())
+		if err != nil {
+			t.Fatalf("GetFeeSchedule failed: %v", err)
+		}
+		if schedule.PaymentFeeBase != "10" || schedule.IssueFeeBase != "1000" {
+			t.Errorf("Unexpected fee schedule: %+v", schedule)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 request while cached, got %d", calls)
+	}
+
+	// Expire the cache and expect a refetch.
+	client.feeSchedule.mu.Lock()
+	client.feeSchedule.fetchedAt = time.Now().Add(-feeScheduleCacheTTL)
+	client.feeSchedule.mu.Unlock()
+	if _, err := client.GetFeeSchedule(context.Background()); err != nil {
+		t.Fatalf("GetFeeSchedule failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected a refetch after the cache expired, got %d requests", calls)
+	}
+}
+
+func TestGetFeeSchedule_WaitHonoursContext(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		fmt.Fprintln(w, `{"payment_fee_base":"10"}`)
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := newClientInternal(server.URL, WithTimeout(5*time.Second))
+	first := make(chan error, 1)
+	go func() {
+		_, err := client.GetFeeSchedule(context.Background())
+		first <- err
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A second caller joins the slow fetch but gives up at its own deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.GetFeeSchedule(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Waiter blocked for %v despite its deadline", elapsed)
+	}
+
+	release <- struct{}{}
+	if err := <-first; err != nil {
+		t.Errorf("First caller failed: %v", err)
+	}
+	if schedule, err := client.GetFeeSchedule(context.Background()); err != nil || schedule.PaymentFeeBase != "10" {
+		t.Errorf("Expected the cached schedule, got %+v, %v", schedule, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 request, got %d", calls.Load())
+	}
+}
+
+func TestDiscoverPeers(t *testing.T) {
+	var seedURL string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/network/peers" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND", "message":"Endpoint not found"}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		// The seed lists itself among its peers; it must only appear once.
+		fmt.Fprintf(w, `{"peers":["http://10.0.0.2:18555","%s/","http://10.0.0.3:18555","http://10.0.0.2:18555"]}`, seedURL)
+	}))
+	defer server.Close()
+	seedURL = server.URL
+
+	nodes, err := DiscoverPeers(context.Background(), seedURL+"/", WithTimeout(time.Second))
+	if err != nil {
+		t.Fatalf("DiscoverPeers failed: %v", err)
+	}
+	want := []string{seedURL, "http://10.0.0.2:18555", "http://10.0.0.3:18555"}
+	if fmt.Sprint(nodes) != fmt.Sprint(want) {
+		t.Errorf("nodes = %v, want %v", nodes, want)
+	}
+
+	merged := MergeNodeURLs([]string{"http://10.0.0.9:18555", " http://10.0.0.3:18555/ ", ""}, nodes)
+	wantMerged := []string{"http://10.0.0.9:18555", "http://10.0.0.3:18555", seedURL, "http://10.0.0.2:18555"}
+	if fmt.Sprint(merged) != fmt.Sprint(wantMerged) {
+		t.Errorf("merged = %v, want %v", merged, wantMerged)
+	}
+}
+
+func TestDiscoverPeers_SeedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, `{"error_code":"INTERNAL_ERROR", "message":"not ready"}`)
+	}))
+	defer server.Close()
+
+	if _, err := DiscoverPeers(context.Background(), server.URL, WithTimeout(time.Second)); err == nil {
+		t.Fatal("expected error from unhealthy seed")
+	}
+}
+
+func TestParseNodeURLs(t *testing.T) {
+	nodes, err := ParseNodeURLs("192.168.1.1:8080, node.example.com\nhttp://localhost:18555 http://127.0.0.1:18556/", false)
+	if err != nil {
+		t.Fatalf("ParseNodeURLs failed: %v", err)
+	}
+	want := []string{"https://192.168.1.1:8080", "https://node.example.com", "http://localhost:18555", "http://127.0.0.1:18556"}
+	if fmt.Sprint(nodes) != fmt.Sprint(want) {
+		t.Errorf("nodes = %v, want %v", nodes, want)
+	}
+
+	if _, err := ParseNodeURLs("http://192.168.1.1:8080", false); !errors.Is(err, ErrInsecureProductionNode) {
+		t.Errorf("Expected ErrInsecureProductionNode, got %v", err)
+	}
+	nodes, err = ParseNodeURLs("http://192.168.1.1:8080,192.168.1.2:8080", true)
+	if err != nil {
+		t.Fatalf("ParseNodeURLs with allowHTTP failed: %v", err)
+	}
+	if nodes[0] != "http://192.168.1.1:8080" || nodes[1] != "https://192.168.1.2:8080" {
+		t.Errorf("allowHTTP should keep http:// but still default to https://, got %v", nodes)
+	}
+
+	for _, bad := range []string{"ftp://node.example.com", "https://"} {
+		if _, err := ParseNodeURLs(bad, true); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
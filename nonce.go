@@ -0,0 +1,113 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnexpectedNonce is returned by NonceTracker.Check when the account nonce
+// reported by the node cannot result from the operations submitted so far.
+var ErrUnexpectedNonce = errors.New("unexpected account nonce")
+
+// NonceTracker hands out consecutive nonces for one account starting at an
+// initial value, and checks the account nonce observed on chain against the
+// number of operations submitted. It is safe for concurrent use.
+type NonceTracker struct {
+	mu        sync.Mutex
+	initial   uint64
+	submitted uint64
+}
+
+// NewNonceTracker returns a tracker whose first nonce is initial, normally the
+// value returned by GetAccountNonce.
+func NewNonceTracker(initial uint64) *NonceTracker {
+	return &NonceTracker{initial: initial}
+}
+
+// Next returns the nonce for the next operation and counts it as submitted.
+func (t *NonceTracker) Next() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	nonce := t.initial + t.submitted
+	t.submitted++
+	return nonce
+}
+
+// Submitted returns how many nonces Next has handed out.
+func (t *NonceTracker) Submitted() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return int(t.submitted)
+}
+
+// ExpectedAfter returns the account nonce once the i-th (zero-based) submitted
+// operation has been applied.
+func (t *NonceTracker) ExpectedAfter(i int) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.initial + uint64(i) + 1
+}
+
+// Expected returns the account nonce once every submitted operation has been
+// applied.
+func (t *NonceTracker) Expected() uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.initial + t.submitted
+}
+
+// Check validates an account nonce observed on chain and returns how many of
+// the submitted operations it accounts for. A nonce below the initial value or
+// beyond Expected means the tracker is out of sync with the account, for
+// example because another process is sending from it.
+func (t *NonceTracker) Check(observed uint64) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if observed < t.initial || observed > t.initial+t.submitted {
+		return 0, fmt.Errorf("%w: observed %d, expected between %d and %d",
+			ErrUnexpectedNonce, observed, t.initial, t.initial+t.submitted)
+	}
+	return int(observed - t.initial), nil
+}
+
+// Reset restarts the tracker at initial with nothing submitted.
+func (t *NonceTracker) Reset(initial uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.initial = initial
+	t.submitted = 0
+}
+
+// DetectNonceGap compares the account nonce of address against the nonces the
+// caller believes it submitted and returns, in ascending order, the nonces
+// between the account nonce and the highest submitted one that were never
+// submitted. The node applies nonces in order, so every submitted nonce above
+// the first gap is stuck until the gap is filled. An empty result means no
+// gap: the pending nonces, if any, are just not applied yet.
+func (client *Client) DetectNonceGap(ctx context.Context, address string, submittedNonces []uint64) ([]uint64, error) {
+	accountNonce, err := client.GetAccountNonce(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+	submitted := make(map[uint64]bool, len(submittedNonces))
+	var highest uint64
+	for _, nonce := range submittedNonces {
+		submitted[nonce] = true
+		if nonce > highest {
+			highest = nonce
+		}
+	}
+	var missing []uint64
+	for nonce := accountNonce.Nonce; len(submittedNonces) > 0 && nonce < highest; nonce++ {
+		if !submitted[nonce] {
+			missing = append(missing, nonce)
+		}
+	}
+	if len(missing) > 0 && client.logEnabled(LogLevelWarn) {
+		client.logger.Warnf("Account %s is at nonce %d with %d nonce(s) missing below %d: %v",
+			address, accountNonce.Nonce, len(missing), highest, missing)
+	}
+	return missing, nil
+}
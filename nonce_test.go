@@ -0,0 +1,97 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNonceTracker(t *testing.T) {
+	tracker := NewNonceTracker(10)
+	for i := 0; i < 3; i++ {
+		if got := tracker.Next(); got != uint64(10+i) {
+			t.Errorf("Next() = %d, want %d", got, 10+i)
+		}
+		if got := tracker.ExpectedAfter(i); got != uint64(11+i) {
+			t.Errorf("ExpectedAfter(%d) = %d, want %d", i, got, 11+i)
+		}
+	}
+	if tracker.Submitted() != 3 || tracker.Expected() != 13 {
+		t.Errorf("Submitted = %d, Expected = %d; want 3, 13", tracker.Submitted(), tracker.Expected())
+	}
+
+	if applied, err := tracker.Check(12); err != nil || applied != 2 {
+		t.Errorf("Check(12) = %d, %v; want 2, nil", applied, err)
+	}
+	if _, err := tracker.Check(14); !errors.Is(err, ErrUnexpectedNonce) {
+		t.Errorf("Check(14) error = %v, want ErrUnexpectedNonce", err)
+	}
+	if _, err := tracker.Check(9); !errors.Is(err, ErrUnexpectedNonce) {
+		t.Errorf("Check(9) error = %v, want ErrUnexpectedNonce", err)
+	}
+
+	tracker.Reset(20)
+	if tracker.Next() != 20 || tracker.Submitted() != 1 {
+		t.Error("Reset did not restart the tracker")
+	}
+}
+
+func TestNonceTracker_Concurrent(t *testing.T) {
+	tracker := NewNonceTracker(0)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		seen = make(map[uint64]bool)
+	)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := tracker.Next()
+			mu.Lock()
+			seen[n] = true
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 50 || tracker.Expected() != 50 {
+		t.Errorf("got %d distinct nonces, Expected = %d", len(seen), tracker.Expected())
+	}
+}
+
+func TestDetectNonceGap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/accounts/nonce" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintln(w, `{"nonce":5}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	tests := []struct {
+		name      string
+		submitted []uint64
+		want      string
+	}{
+		{"none submitted", nil, "[]"},
+		{"all applied", []uint64{2, 3, 4}, "[]"},
+		{"pending in order", []uint64{5, 6, 7}, "[]"},
+		{"gap", []uint64{3, 4, 6, 9, 8}, "[5 7]"},
+	}
+	for _, tt := range tests {
+		missing, err := client.DetectNonceGap(context.Background(), "0x0000000000000000000000000000000000000001", tt.submitted)
+		if err != nil {
+			t.Fatalf("%s: DetectNonceGap failed: %v", tt.name, err)
+		}
+		if got := fmt.Sprint(missing); got != tt.want {
+			t.Errorf("%s: missing = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package onemoney
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rateLimitSweepInterval is how often a PerAddressRateLimiter drops addresses
+// that have been idle long enough to have no pending slot.
+const rateLimitSweepInterval = time.Minute
+
+// PerAddressRateLimiter spaces out sends to the same recipient address so a
+// single address is not flooded, independently of any per-node limits.
+// Each address is limited separately; addresses without an explicit limit use
+// the default rate, and a rate of zero or less means unlimited.
+type PerAddressRateLimiter struct {
+	mu         sync.Mutex
+	defaultTPS int
+	limits     map[string]int
+	next       map[string]time.Time
+	sweepAt    time.Time
+}
+
+// NewPerAddressRateLimiter creates a limiter allowing defaultTPS sends per second
+// to each address.
+func NewPerAddressRateLimiter(defaultTPS int) *PerAddressRateLimiter {
+	return &PerAddressRateLimiter{
+		defaultTPS: defaultTPS,
+		limits:     make(map[string]int),
+		next:       make(map[string]time.Time),
+	}
+}
+
+// WithAddressRateLimit overrides the rate for one address and returns the limiter
+// so calls can be chained.
+func (l *PerAddressRateLimiter) WithAddressRateLimit(address string, tps int) *PerAddressRateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits[strings.ToLower(address)] = tps
+	return l
+}
+
+// WaitFor blocks until a send to address is allowed or ctx is done. If ctx is
+// done first and no later send to the address has been scheduled, the slot is
+// handed back, so a cancelled wait does not delay the next caller.
+func (l *PerAddressRateLimiter) WaitFor(ctx context.Context, address string) error {
+	key := strings.ToLower(address)
+
+	l.mu.Lock()
+	tps, ok := l.limits[key]
+	if !ok {
+		tps = l.defaultTPS
+	}
+	if tps <= 0 {
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+	now := time.Now()
+	l.sweep(now)
+	slot := l.next[key]
+	if slot.Before(now) {
+		slot = now
+	}
+	reserved := slot.Add(time.Second / time.Duration(tps))
+	l.next[key] = reserved
+	l.mu.Unlock()
+
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		if l.next[key].Equal(reserved) {
+			l.next[key] = slot
+		}
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// sweep drops addresses whose next slot has passed, at most once per
+// rateLimitSweepInterval. Their entries are equivalent to no entry at all.
+// l.mu must be held.
+func (l *PerAddressRateLimiter) sweep(now time.Time) {
+	if now.Before(l.sweepAt) {
+		return
+	}
+	for key, next := range l.next {
+		if !next.After(now) {
+			delete(l.next, key)
+		}
+	}
+	l.sweepAt = now.Add(rateLimitSweepInterval)
+}
+
+// ResponseHeaderCallback receives the headers of every HTTP response the client
+// gets, including error responses. It runs on the request goroutine before the
+// body is decoded, so it should return quickly.
+type ResponseHeaderCallback func(method, url string, statusCode int, header http.Header)
+
+// WithResponseHeaderCallback registers cb to observe response headers, for
+// example the X-RateLimit-* headers some nodes send. If names are given, only
+// those headers are passed; otherwise cb gets a copy of all of them.
+func WithResponseHeaderCallback(cb ResponseHeaderCallback, names ...string) ClientOption {
+	return func(c *Client) {
+		c.headerCallback = cb
+		c.headerNames = make([]string, len(names))
+		for i, name := range names {
+			c.headerNames[i] = http.CanonicalHeaderKey(name)
+		}
+	}
+}
+
+func (client *Client) notifyResponseHeaders(method, url string, resp *http.Response) {
+	if client.headerCallback == nil {
+		return
+	}
+	var header http.Header
+	if len(client.headerNames) == 0 {
+		header = resp.Header.Clone()
+	} else {
+		header = make(http.Header, len(client.headerNames))
+		for _, name := range client.headerNames {
+			if values := resp.Header.Values(name); len(values) > 0 {
+				header[name] = append([]string(nil), values...)
+			}
+		}
+	}
+	client.headerCallback(method, url, resp.StatusCode, header)
+}
+
+// RateLimitInfo is the quota a node advertises in its X-RateLimit-* headers.
+type RateLimitInfo struct {
+	Limit     int
+	Remaining int
+	// Reset is when the quota refills; zero if the node did not say.
+	Reset time.Time
+}
+
+// ParseRateLimitHeaders reads X-RateLimit-Limit, X-RateLimit-Remaining and
+// X-RateLimit-Reset. Reset is accepted either as seconds from now or as a Unix
+// timestamp. ok is false when X-RateLimit-Remaining is missing or invalid.
+func ParseRateLimitHeaders(header http.Header) (info RateLimitInfo, ok bool) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return RateLimitInfo{}, false
+	}
+	info.Remaining = remaining
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		info.Limit = limit
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil && reset >= 0 {
+		// Values this large cannot be a delay in seconds; treat them as epoch time.
+		if reset >= 1_000_000_000 {
+			info.Reset = time.Unix(reset, 0)
+		} else {
+			info.Reset = time.Now().Add(time.Duration(reset) * time.Second)
+		}
+	}
+	return info, true
+}
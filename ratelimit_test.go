@@ -0,0 +1,185 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPerAddressRateLimiter_IndependentAddresses(t *testing.T) {
+	limiter := NewPerAddressRateLimiter(20)
+	addresses := []string{
+		"0x00000000000000000000000000000000000000aa",
+		"0x00000000000000000000000000000000000000bb",
+	}
+
+	var mu sync.Mutex
+	done := make(map[string][]time.Time)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for _, address := range addresses {
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(address string) {
+				defer wg.Done()
+				if err := limiter.WaitFor(context.Background(), address); err != nil {
+					t.Errorf("WaitFor failed: %v", err)
+					return
+				}
+				mu.Lock()
+				done[address] = append(done[address], time.Now())
+				mu.Unlock()
+			}(address)
+		}
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	// 10 sends at 20 TPS need ~450ms per address. Limited independently, both
+	// addresses finish in about that time rather than twice as long.
+	if elapsed < 400*time.Millisecond {
+		t.Errorf("Expected sends to be rate limited, finished in %v", elapsed)
+	}
+	if elapsed > 850*time.Millisecond {
+		t.Errorf("Expected addresses to be limited independently, took %v", elapsed)
+	}
+	for _, address := range addresses {
+		if len(done[address]) != 10 {
+			t.Errorf("Expected 10 sends to %s, got %d", address, len(done[address]))
+		}
+	}
+}
+
+func TestPerAddressRateLimiter_Override(t *testing.T) {
+	address := "0x00000000000000000000000000000000000000AA"
+	limiter := NewPerAddressRateLimiter(0).WithAddressRateLimit(address, 5)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := limiter.WaitFor(ctx, address); err != nil {
+		t.Fatalf("First send should not wait, got %v", err)
+	}
+	if err := limiter.WaitFor(ctx, "0x00000000000000000000000000000000000000aa"); err == nil {
+		t.Error("Expected the second send to the same address to exceed the context deadline")
+	}
+	if err := limiter.WaitFor(context.Background(), "0x00000000000000000000000000000000000000cc"); err != nil {
+		t.Errorf("Expected unlimited default rate for other addresses, got %v", err)
+	}
+}
+
+func TestPerAddressRateLimiter_CancelRefundsSlot(t *testing.T) {
+	address := "0x00000000000000000000000000000000000000aa"
+	limiter := NewPerAddressRateLimiter(10)
+	start := time.Now()
+	if err := limiter.WaitFor(context.Background(), address); err != nil {
+		t.Fatalf("First send should not wait, got %v", err)
+	}
+
+	// The second caller gives up before its slot at +100ms.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.WaitFor(ctx, address); err == nil {
+		t.Fatal("Expected the second send to hit its deadline")
+	}
+
+	// The third caller gets the refunded slot at +100ms, not +200ms.
+	if err := limiter.WaitFor(context.Background(), address); err != nil {
+		t.Fatalf("WaitFor failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 180*time.Millisecond {
+		t.Errorf("Expected the cancelled slot to be reused, third send took %v", elapsed)
+	}
+}
+
+func TestPerAddressRateLimiter_EvictsIdleAddresses(t *testing.T) {
+	limiter := NewPerAddressRateLimiter(1000)
+	for i := 0; i < 100; i++ {
+		limiter.WaitFor(context.Background(), fmt.Sprintf("0x%040x", i))
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	limiter.mu.Lock()
+	limiter.sweepAt = time.Time{}
+	limiter.mu.Unlock()
+	limiter.WaitFor(context.Background(), "0x00000000000000000000000000000000000000ff")
+
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if len(limiter.next) != 1 {
+		t.Errorf("Expected idle addresses to be evicted, %d remain", len(limiter.next))
+	}
+}
+
+func TestWithResponseHeaderCallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "30")
+		w.Header().Set("X-Other", "ignored")
+		if r.URL.Path == "/v1/checkpoints/number" {
+			fmt.Fprintln(w, `{"number":5}`)
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprintln(w, `{"error_code":"RATE_LIMITED","message":"slow down"}`)
+	}))
+	defer server.Close()
+
+	type call struct {
+		method string
+		status int
+		header http.Header
+	}
+	var calls []call
+	cb := func(method, url string, statusCode int, header http.Header) {
+		calls = append(calls, call{method, statusCode, header})
+	}
+	client := newClientInternal(server.URL, WithTimeout(time.Second),
+		WithResponseHeaderCallback(cb, "x-ratelimit-remaining", "X-RateLimit-Reset", "X-RateLimit-Limit"))
+
+	if _, err := client.GetCheckpointNumber(context.Background()); err != nil {
+		t.Fatalf("GetCheckpointNumber failed: %v", err)
+	}
+	if err := client.PostMethod(context.Background(), "/v1/transactions/payment", map[string]string{}, nil); !IsRateLimited(err) {
+		t.Fatalf("Expected RATE_LIMITED, got %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 callback calls, got %d", len(calls))
+	}
+	if calls[0].method != "GET" || calls[0].status != http.StatusOK || calls[1].status != http.StatusTooManyRequests {
+		t.Errorf("Unexpected calls: %+v", calls)
+	}
+	if calls[0].header.Get("X-Other") != "" {
+		t.Error("Headers not in the filter list should not be passed")
+	}
+
+	start := time.Now()
+	info, ok := ParseRateLimitHeaders(calls[1].header)
+	if !ok {
+		t.Fatal("ParseRateLimitHeaders returned ok=false")
+	}
+	if info.Limit != 100 || info.Remaining != 42 {
+		t.Errorf("info = %+v", info)
+	}
+	if d := info.Reset.Sub(start); d < 29*time.Second || d > 31*time.Second {
+		t.Errorf("Reset should be about 30s from now, got %v", d)
+	}
+}
+
+func TestParseRateLimitHeaders(t *testing.T) {
+	h := http.Header{}
+	if _, ok := ParseRateLimitHeaders(h); ok {
+		t.Error("Expected ok=false without X-RateLimit-Remaining")
+	}
+	h.Set("X-RateLimit-Remaining", "0")
+	h.Set("X-RateLimit-Reset", "1900000000")
+	info, ok := ParseRateLimitHeaders(h)
+	if !ok || info.Remaining != 0 || !info.Reset.Equal(time.Unix(1900000000, 0)) {
+		t.Errorf("info = %+v, ok = %v", info, ok)
+	}
+}
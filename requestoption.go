@@ -0,0 +1,139 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// RequestOption adjusts a single GetMethodWith or PostMethodWith call, layered
+// over the client's defaults.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	timeout time.Duration
+	headers http.Header
+	retries int
+}
+
+type requestOptionsCtxKey struct{}
+
+// WithRequestTimeout bounds each attempt of this call, including every
+// WithRequestRetries retry, by d. It replaces the client's WithTimeout value
+// for this call, so it can also give one slow call more time.
+func WithRequestTimeout(d time.Duration) RequestOption {
+	return func(o *requestOptions) {
+		o.timeout = d
+	}
+}
+
+// WithRequestHeader adds a header to this call. It is set after the client's
+// own headers, so it can override them.
+func WithRequestHeader(key, value string) RequestOption {
+	return func(o *requestOptions) {
+		if o.headers == nil {
+			o.headers = make(http.Header)
+		}
+		o.headers.Add(key, value)
+	}
+}
+
+// WithRequestRetries retries this call up to n more times after a network
+// error or a 5xx response. With WithRetryBudget set, each retry also needs a
+// grant from the shared budget. POST retries are only safe when the node
+// deduplicates them, e.g. with WithIdempotencyKey.
+func WithRequestRetries(n int) RequestOption {
+	return func(o *requestOptions) {
+		o.retries = n
+	}
+}
+
+// GetMethodWith is GetMethod with per-call options.
+func (client *Client) GetMethodWith(ctx context.Context, path string, result interface{}, opts ...RequestOption) error {
+	return client.withRequestOptions(ctx, opts, func(ctx context.Context) error {
+		return client.GetMethod(ctx, path, result)
+	})
+}
+
+// PostMethodWith is PostMethod with per-call options.
+func (client *Client) PostMethodWith(ctx context.Context, path string, body interface{}, result interface{}, opts ...RequestOption) error {
+	return client.withRequestOptions(ctx, opts, func(ctx context.Context) error {
+		return client.PostMethod(ctx, path, body, result)
+	})
+}
+
+func (client *Client) withRequestOptions(ctx context.Context, opts []RequestOption, call func(ctx context.Context) error) error {
+	o := new(requestOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	ctx = context.WithValue(ctx, requestOptionsCtxKey{}, o)
+	attemptCtx := ctx
+	for attempt := 0; ; attempt++ {
+		err := client.requestAttempt(attemptCtx, o.timeout, call)
+		if err == nil || !isRetryableRequestError(err) || ctx.Err() != nil {
+			return err
+		}
+		if attempt >= o.retries {
+			if attempt > 0 {
+				return fmt.Errorf("request failed after %d retries: %w", attempt, err)
+			}
+			return err
+		}
+		if client.retryBudget != nil && !client.retryBudget.TryRetry() {
+			return fmt.Errorf("%w after %d retries: %w", ErrRetryBudgetExhausted, attempt, err)
+		}
+		if client.logEnabled(LogLevelWarn) {
+			client.logger.Warnf("Request failed, retrying (attempt %d/%d): %v", attempt+1, o.retries, err)
+		}
+		attemptCtx = withRetryAttempt(ctx)
+	}
+}
+
+// requestAttempt runs one attempt of call, bounded by timeout if set.
+func (client *Client) requestAttempt(ctx context.Context, timeout time.Duration, call func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return call(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return call(ctx)
+}
+
+// httpClientFor returns the http.Client to send a request with: the client's
+// own, or a copy carrying the per-call timeout in place of the client's.
+func (client *Client) httpClientFor(ctx context.Context) *http.Client {
+	o := requestOptionsFromContext(ctx)
+	if o == nil || o.timeout <= 0 {
+		return client.httpclient
+	}
+	httpclient := *client.httpclient
+	httpclient.Timeout = o.timeout
+	return &httpclient
+}
+
+func isRetryableRequestError(err error) bool {
+	var netErr *NetworkError
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusInternalServerError
+}
+
+func requestOptionsFromContext(ctx context.Context) *requestOptions {
+	o, _ := ctx.Value(requestOptionsCtxKey{}).(*requestOptions)
+	return o
+}
+
+func setRequestOptionHeaders(ctx context.Context, req *http.Request) {
+	o := requestOptionsFromContext(ctx)
+	if o == nil {
+		return
+	}
+	for key, values := range o.headers {
+		req.Header[key] = values
+	}
+}
@@ -0,0 +1,81 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetMethodWith(t *testing.T) {
+	var calls, slowCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/slow_once":
+			if atomic.AddInt32(&slowCalls, 1) == 1 {
+				time.Sleep(200 * time.Millisecond)
+			}
+			fmt.Fprintln(w, `{"chain_id":1}`)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+			fmt.Fprintln(w, `{"chain_id":1}`)
+		case "/flaky":
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprintln(w, `{"error_code":"UNAVAILABLE","message":"try again"}`)
+				return
+			}
+			fmt.Fprintln(w, `{"chain_id":1}`)
+		case "/header":
+			if r.Header.Get("X-Trace") != "abc" || r.Header.Get(ClientNameHeader) != "override" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `{"chain_id":1}`)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithClientName("default"))
+	ctx := context.Background()
+	var result ChainIdResponse
+
+	// The timeout applies to each attempt, so the retry gets a fresh deadline.
+	if err := client.GetMethodWith(ctx, "/slow_once", &result, WithRequestTimeout(50*time.Millisecond)); err == nil {
+		t.Fatal("Expected the per-call timeout to cut off the slow attempt")
+	}
+	atomic.StoreInt32(&slowCalls, 0)
+	if err := client.GetMethodWith(ctx, "/slow_once", &result, WithRequestTimeout(50*time.Millisecond), WithRequestRetries(1)); err != nil {
+		t.Errorf("Expected the retry to succeed within its own timeout, got %v", err)
+	}
+
+	// A per-call timeout replaces the client timeout, so it can lengthen it too.
+	short := newClientInternal(server.URL, WithTimeout(100*time.Millisecond))
+	if err := short.GetMethod(ctx, "/slow", &result); err == nil {
+		t.Fatal("Expected the client timeout to cut off the slow call")
+	}
+	if err := short.GetMethodWith(ctx, "/slow", &result, WithRequestTimeout(time.Second)); err != nil {
+		t.Errorf("Expected the longer per-call timeout to let the slow call finish, got %v", err)
+	}
+
+	if err := client.GetMethodWith(ctx, "/header", &result,
+		WithRequestHeader("X-Trace", "abc"), WithRequestHeader(ClientNameHeader, "override")); err != nil {
+		t.Errorf("Expected the per-call headers to be sent, got %v", err)
+	}
+
+	var apiErr *APIError
+	if err := client.GetMethodWith(ctx, "/flaky", &result, WithRequestRetries(1)); !errors.As(err, &apiErr) {
+		t.Errorf("Expected a 503 after one retry, got %v", err)
+	}
+	atomic.StoreInt32(&calls, 0)
+	if err := client.GetMethodWith(ctx, "/flaky", &result, WithRequestRetries(2)); err != nil {
+		t.Errorf("Expected success on the third attempt, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
@@ -0,0 +1,78 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// AutoResign signs payload with privateKey and passes the signature to submit.
+// If submit fails with a nonce conflict, the payload's nonce is refreshed from
+// the node, the payload is re-signed and submit is called again, up to
+// maxRetries times. The new nonce is the account nonce when the rejected one
+// was ahead of it, and otherwise the later of the account nonce and the
+// rejected nonce plus one. Any other error is returned immediately. With
+// WithRetryBudget set, each retry also needs a grant from the shared budget and
+// ErrRetryBudgetExhausted is returned once it runs out.
+//
+// payload must be a pointer to a payload struct with a `Nonce uint64` field
+// (e.g. *PaymentPayload); submit is expected to build the request from the same
+// pointer so it always sends the nonce that was signed.
+func (client *Client) AutoResign(ctx context.Context, payload interface{}, privateKey string, maxRetries int,
+	submit func(ctx context.Context, signature *Signature) error) error {
+	nonceField, err := payloadNonceField(payload)
+	if err != nil {
+		return err
+	}
+	attemptCtx := ctx
+	for attempt := 0; ; attempt++ {
+		signature, err := client.SignMessage(payload, privateKey)
+		if err != nil {
+			return err
+		}
+		err = submit(attemptCtx, signature)
+		if err == nil || !IsNonceConflict(err) {
+			return err
+		}
+		if attempt >= maxRetries {
+			return fmt.Errorf("nonce conflict after %d retries: %w", attempt, err)
+		}
+		if client.retryBudget != nil && !client.retryBudget.TryRetry() {
+			return fmt.Errorf("%w after %d retries: %w", ErrRetryBudgetExhausted, attempt, err)
+		}
+		if client.logEnabled(LogLevelWarn) {
+			client.logger.Warnf("Nonce %d rejected, re-signing with a fresh nonce (attempt %d/%d)", nonceField.Uint(), attempt+1, maxRetries)
+		}
+		address, err := PrivateKeyToAddress(privateKey)
+		if err != nil {
+			return err
+		}
+		// The refresh and the resubmission are part of the retry, so they are
+		// not recorded as new requests against the retry budget.
+		attemptCtx = withRetryAttempt(ctx)
+		accountNonce, err := client.GetAccountNonce(attemptCtx, address)
+		if err != nil {
+			return fmt.Errorf("refresh nonce: %w", err)
+		}
+		// INVALID_NONCE also covers a nonce ahead of the account (a gap); bumping
+		// it further would only widen the gap, so fall back to the chain's nonce.
+		next := nonceField.Uint() + 1
+		if accountNonce.Nonce > next || accountNonce.Nonce < nonceField.Uint() {
+			next = accountNonce.Nonce
+		}
+		nonceField.SetUint(next)
+	}
+}
+
+// payloadNonceField returns the settable Nonce field of a pointer to a payload struct.
+func payloadNonceField(payload interface{}) (reflect.Value, error) {
+	v := reflect.ValueOf(payload)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("payload must be a non-nil pointer to a struct, got %T", payload)
+	}
+	field := v.Elem().FieldByName("Nonce")
+	if !field.IsValid() || field.Kind() != reflect.Uint64 {
+		return reflect.Value{}, fmt.Errorf("payload %T has no uint64 Nonce field", payload)
+	}
+	return field, nil
+}
@@ -0,0 +1,128 @@
+package onemoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testPrivateKey = "0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+func TestAutoResign(t *testing.T) {
+	var mu sync.Mutex
+	var submittedNonces []uint64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/nonce":
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"nonce":8}`)
+		case "/v1/transactions/payment":
+			var req PaymentRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			mu.Lock()
+			submittedNonces = append(submittedNonces, req.Nonce)
+			first := len(submittedNonces) == 1
+			mu.Unlock()
+			if first {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"error_code":"%s","message":"nonce already used"}`, ErrCodeNonceConflict)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintln(w, `{"hash":"0xabc"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	payload := &PaymentPayload{
+		RecentCheckpoint: 100,
+		ChainID:          1212101,
+		Nonce:            5,
+		Recipient:        common.HexToAddress("0x0000000000000000000000000000000000000002"),
+		Value:            NewTokenValue(big.NewInt(10)),
+		Token:            common.HexToAddress("0x0000000000000000000000000000000000000003"),
+	}
+	var hash string
+	err := client.AutoResign(context.Background(), payload, testPrivateKey, 3, func(ctx context.Context, signature *Signature) error {
+		resp, err := client.SendPayment(ctx, &PaymentRequest{PaymentPayload: *payload, Signature: *signature})
+		if err != nil {
+			return err
+		}
+		hash = resp.Hash
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("AutoResign failed: %v", err)
+	}
+	if hash != "0xabc" {
+		t.Errorf("Expected hash '0xabc', got '%s'", hash)
+	}
+	if len(submittedNonces) != 2 || submittedNonces[0] != 5 || submittedNonces[1] != 8 {
+		t.Errorf("Expected submitted nonces [5 8], got %v", submittedNonces)
+	}
+}
+
+func TestAutoResign_InvalidPayload(t *testing.T) {
+	client := newClientInternal("http://localhost:12345")
+	submit := func(ctx context.Context, signature *Signature) error { return nil }
+	if err := client.AutoResign(context.Background(), PaymentPayload{}, testPrivateKey, 1, submit); err == nil {
+		t.Error("Expected AutoResign to reject a non-pointer payload")
+	}
+	if err := client.AutoResign(context.Background(), &struct{ Value uint64 }{}, testPrivateKey, 1, submit); err == nil {
+		t.Error("Expected AutoResign to reject a payload without a Nonce field")
+	}
+}
+
+func TestAutoResign_NonceAhead(t *testing.T) {
+	var submittedNonces []uint64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/nonce":
+			fmt.Fprintln(w, `{"nonce":3}`)
+		case "/v1/transactions/payment":
+			var req PaymentRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			submittedNonces = append(submittedNonces, req.Nonce)
+			if req.Nonce != 3 {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"error_code":"%s","message":"expected nonce 3"}`, ErrCodeInvalidNonce)
+				return
+			}
+			fmt.Fprintln(w, `{"hash":"0xabc"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	payload := &PaymentPayload{ChainID: 1212101, Nonce: 5, Value: NewTokenValue(big.NewInt(10)), Token: common.HexToAddress("0x3")}
+	err := client.AutoResign(context.Background(), payload, testPrivateKey, 3, func(ctx context.Context, signature *Signature) error {
+		_, err := client.SendPayment(ctx, &PaymentRequest{PaymentPayload: *payload, Signature: *signature})
+		return err
+	})
+	if err != nil {
+		t.Fatalf("AutoResign failed: %v", err)
+	}
+	// A nonce ahead of the account falls back to the account nonce rather than growing the gap.
+	if fmt.Sprint(submittedNonces) != "[5 3]" {
+		t.Errorf("Expected submitted nonces [5 3], got %v", submittedNonces)
+	}
+}
@@ -0,0 +1,89 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BaseHostResolver returns the base URL of the node to use, for example from
+// Consul or a DNS SRV lookup.
+type BaseHostResolver func(ctx context.Context) (string, error)
+
+// defaultResolverTTL is how long a resolved base host is reused.
+const defaultResolverTTL = 10 * time.Second
+
+type hostResolver struct {
+	resolve BaseHostResolver
+	ttl     time.Duration
+
+	mu         sync.Mutex
+	host       string
+	resolvedAt time.Time
+}
+
+// WithBaseHostResolver makes the client ask resolve for its base host instead
+// of using a fixed one. The answer is reused for 10 seconds, and dropped early
+// when a request to it fails with a NetworkError. With WithFallbackURLs the
+// resolved host takes the place of the primary.
+func WithBaseHostResolver(resolve BaseHostResolver) ClientOption {
+	return func(c *Client) {
+		c.resolver = &hostResolver{resolve: resolve, ttl: defaultResolverTTL}
+	}
+}
+
+// primaryHost returns the resolved base host, or the static one when no
+// resolver is set.
+func (client *Client) primaryHost(ctx context.Context) (string, error) {
+	r := client.resolver
+	if r == nil {
+		return client.baseHost, nil
+	}
+	r.mu.Lock()
+	cached, fresh := r.host, r.host != "" && time.Since(r.resolvedAt) < r.ttl
+	r.mu.Unlock()
+	if fresh {
+		return cached, nil
+	}
+
+	// The lock is not held while resolving, so a slow lookup does not block
+	// requests that can still use the cache, and a resolver that calls back
+	// into the client cannot deadlock. Concurrent misses may each resolve.
+	host, err := r.resolve(ctx)
+	if err != nil {
+		return "", fmt.Errorf("resolve base host: %w", err)
+	}
+	if client.logEnabled(LogLevelDebug) && host != cached {
+		client.logger.Printf("Resolved base host %s", host)
+	}
+	r.mu.Lock()
+	r.host = host
+	r.resolvedAt = time.Now()
+	r.mu.Unlock()
+	return host, nil
+}
+
+// lastPrimaryHost returns the most recently resolved base host without
+// resolving, falling back to the static one.
+func (client *Client) lastPrimaryHost() string {
+	r := client.resolver
+	if r == nil {
+		return client.baseHost
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.host == "" {
+		return client.baseHost
+	}
+	return r.host
+}
+
+// invalidateResolvedHost makes the next request resolve the base host again.
+func (client *Client) invalidateResolvedHost() {
+	if r := client.resolver; r != nil {
+		r.mu.Lock()
+		r.resolvedAt = time.Time{}
+		r.mu.Unlock()
+	}
+}
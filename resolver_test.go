@@ -0,0 +1,101 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithBaseHostResolver(t *testing.T) {
+	node := func(chainID int) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"chain_id":%d}`, chainID)
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	first, second := node(1), node(2)
+
+	var resolves atomic.Int32
+	var target atomic.Value
+	target.Store(first.URL)
+	resolve := func(ctx context.Context) (string, error) {
+		resolves.Add(1)
+		return target.Load().(string), nil
+	}
+	client := newClientInternal("http://unused.invalid", WithTimeout(time.Second), WithBaseHostResolver(resolve))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		result, err := client.GetChainId(ctx)
+		if err != nil || result.ChainId != 1 {
+			t.Fatalf("GetChainId = %+v, %v; want chain 1", result, err)
+		}
+	}
+	if resolves.Load() != 1 {
+		t.Errorf("Expected the resolved host to be cached, got %d resolves", resolves.Load())
+	}
+	if client.ActiveURL() != first.URL {
+		t.Errorf("ActiveURL = %s, want %s", client.ActiveURL(), first.URL)
+	}
+
+	// Once the TTL passes the resolver is asked again.
+	target.Store(second.URL)
+	client.resolver.ttl = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	if result, err := client.GetChainId(ctx); err != nil || result.ChainId != 2 {
+		t.Errorf("GetChainId after TTL = %+v, %v; want chain 2", result, err)
+	}
+
+	// A network error drops the cached host even within the TTL.
+	client.resolver.ttl = time.Hour
+	second.Close()
+	if _, err := client.GetChainId(ctx); err == nil {
+		t.Fatal("Expected a network error from the closed node")
+	}
+	target.Store(first.URL)
+	if result, err := client.GetChainId(ctx); err != nil || result.ChainId != 1 {
+		t.Errorf("GetChainId after network error = %+v, %v; want chain 1", result, err)
+	}
+}
+
+func TestWithBaseHostResolver_Error(t *testing.T) {
+	errDiscovery := errors.New("no healthy nodes")
+	client := newClientInternal("http://unused.invalid", WithTimeout(time.Second),
+		WithBaseHostResolver(func(ctx context.Context) (string, error) { return "", errDiscovery }))
+	if _, err := client.GetChainId(context.Background()); !errors.Is(err, errDiscovery) {
+		t.Errorf("Expected the resolver error, got %v", err)
+	}
+}
+
+func TestWithBaseHostResolver_CallsBackIntoClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"chain_id":1}`)
+	}))
+	defer server.Close()
+
+	var client *Client
+	client = newClientInternal("", WithTimeout(time.Second), WithBaseHostResolver(func(ctx context.Context) (string, error) {
+		// Reading the client's state from the resolver must not deadlock.
+		_ = client.ActiveURL()
+		return server.URL, nil
+	}))
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.GetChainId(context.Background())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("GetChainId failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("resolver calling back into the client deadlocked")
+	}
+}
@@ -0,0 +1,132 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrRetryBudgetExhausted is returned instead of retrying once the client's
+// RetryBudget has no retries left.
+var ErrRetryBudgetExhausted = errors.New("retry budget exhausted")
+
+// RetryBudget caps retries across every goroutine sharing it, so that a failing
+// node cannot multiply the request volume. A budget built with
+// NewRatioRetryBudget allows minRetries plus ratio times the number of
+// requests recorded so far; one built with NewRetryBudget allows a fixed total.
+type RetryBudget struct {
+	mu         sync.Mutex
+	ratio      float64
+	minRetries int64
+	requests   int64
+	retries    int64
+	denied     int64
+}
+
+// RetryBudgetStats is a snapshot of a RetryBudget's counters.
+type RetryBudgetStats struct {
+	Requests int64
+	Retries  int64
+	Denied   int64
+	// Remaining is how many retries would currently be granted.
+	Remaining int64
+}
+
+// NewRatioRetryBudget returns a budget that allows retries up to ratio (e.g.
+// 0.1 for 10%) of the recorded requests, plus minRetries so that the first
+// failures of a run can still be retried.
+func NewRatioRetryBudget(ratio float64, minRetries int) *RetryBudget {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if minRetries < 0 {
+		minRetries = 0
+	}
+	return &RetryBudget{ratio: ratio, minRetries: int64(minRetries)}
+}
+
+// NewRetryBudget returns a budget of total retries that does not grow with
+// the number of requests.
+func NewRetryBudget(total int) *RetryBudget {
+	return NewRatioRetryBudget(0, total)
+}
+
+// WithRetryBudget shares budget with the client: every first attempt of a
+// request is recorded against it, and retries, failover attempts and AutoResign
+// resubmissions each need a grant from it. Retries are not recorded, so they
+// cannot earn budget for more retries. One budget may be shared by many clients.
+func WithRetryBudget(budget *RetryBudget) ClientOption {
+	return func(c *Client) {
+		c.retryBudget = budget
+	}
+}
+
+// RecordRequest counts one request towards the budget. Only first attempts
+// should be recorded.
+func (b *RetryBudget) RecordRequest() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests++
+}
+
+// TryRetry consumes one retry and reports whether it was granted.
+func (b *RetryBudget) TryRetry() bool {
+	_, ok := b.Reserve(1)
+	return ok
+}
+
+// Reserve consumes up to n retries and returns how many were granted. ok is
+// false when none were.
+func (b *RetryBudget) Reserve(n int) (granted int, ok bool) {
+	if n <= 0 {
+		return 0, true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	remaining := b.remaining()
+	if remaining <= 0 {
+		b.denied++
+		return 0, false
+	}
+	if int64(n) > remaining {
+		n = int(remaining)
+	}
+	b.retries += int64(n)
+	return n, true
+}
+
+// Stats returns the current counters.
+func (b *RetryBudget) Stats() RetryBudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return RetryBudgetStats{
+		Requests:  b.requests,
+		Retries:   b.retries,
+		Denied:    b.denied,
+		Remaining: b.remaining(),
+	}
+}
+
+func (b *RetryBudget) remaining() int64 {
+	allowed := b.minRetries + int64(b.ratio*float64(b.requests))
+	return allowed - b.retries
+}
+
+type retryAttemptCtxKey struct{}
+
+// withRetryAttempt marks ctx as carrying a retry, so its requests are not
+// recorded against the retry budget.
+func withRetryAttempt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryAttemptCtxKey{}, true)
+}
+
+// recordRequest records a request against the retry budget unless ctx carries
+// a retry.
+func (client *Client) recordRequest(ctx context.Context) {
+	if client.retryBudget == nil {
+		return
+	}
+	if retry, _ := ctx.Value(retryAttemptCtxKey{}).(bool); !retry {
+		client.retryBudget.RecordRequest()
+	}
+}
@@ -0,0 +1,140 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRatioRetryBudget(t *testing.T) {
+	budget := NewRatioRetryBudget(0.1, 1)
+	if !budget.TryRetry() {
+		t.Fatal("minRetries should allow the first retry")
+	}
+	if budget.TryRetry() {
+		t.Fatal("second retry should be denied before any requests")
+	}
+	for i := 0; i < 20; i++ {
+		budget.RecordRequest()
+	}
+	// 1 + 10% of 20 = 3 retries in total, one already used.
+	granted := 0
+	for i := 0; i < 5; i++ {
+		if budget.TryRetry() {
+			granted++
+		}
+	}
+	if granted != 2 {
+		t.Errorf("granted %d retries, want 2", granted)
+	}
+	stats := budget.Stats()
+	if stats.Requests != 20 || stats.Retries != 3 || stats.Denied != 4 || stats.Remaining != 0 {
+		t.Errorf("Stats = %+v", stats)
+	}
+}
+
+func TestRetryBudget_Reserve(t *testing.T) {
+	budget := NewRetryBudget(10)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if n, ok := budget.Reserve(1); !ok || n != 1 {
+				t.Errorf("Reserve(1) = %d, %v; want 1, true", n, ok)
+			}
+		}()
+	}
+	wg.Wait()
+	if n, ok := budget.Reserve(1); ok || n != 0 {
+		t.Errorf("Reserve on exhausted budget = %d, %v; want 0, false", n, ok)
+	}
+	if stats := budget.Stats(); stats.Retries != 10 || stats.Denied != 1 || stats.Remaining != 0 {
+		t.Errorf("Stats = %+v", stats)
+	}
+
+	partial := NewRetryBudget(3)
+	if n, ok := partial.Reserve(5); !ok || n != 3 {
+		t.Errorf("Reserve(5) from 3 = %d, %v; want 3, true", n, ok)
+	}
+}
+
+func TestAutoResign_RetryBudgetExhausted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/nonce":
+			fmt.Fprintln(w, `{"nonce":8}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error_code":"%s","message":"nonce already used"}`, ErrCodeNonceConflict)
+		}
+	}))
+	defer server.Close()
+
+	budget := NewRatioRetryBudget(0, 1)
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithRetryBudget(budget))
+	payload := &PaymentPayload{ChainID: 1212101, Nonce: 5, Value: NewTokenValue(big.NewInt(10)), Token: common.HexToAddress("0x3")}
+	submits := 0
+	err := client.AutoResign(context.Background(), payload, testPrivateKey, 5, func(ctx context.Context, signature *Signature) error {
+		submits++
+		_, err := client.SendPayment(ctx, &PaymentRequest{PaymentPayload: *payload, Signature: *signature})
+		return err
+	})
+	if !errors.Is(err, ErrRetryBudgetExhausted) {
+		t.Fatalf("Expected ErrRetryBudgetExhausted, got %v", err)
+	}
+	if !IsNonceConflict(err) {
+		t.Errorf("The last API error should still be wrapped: %v", err)
+	}
+	if submits != 2 {
+		t.Errorf("Expected 2 submissions (1 retry), got %d", submits)
+	}
+	// Only the first submission is recorded; the nonce refresh and the
+	// resubmission belong to the retry.
+	stats := budget.Stats()
+	if stats.Retries != 1 || stats.Denied != 1 || stats.Requests != 1 {
+		t.Errorf("Stats = %+v, want 1 request, 1 retry, 1 denied", stats)
+	}
+}
+
+func TestRetryBudget_RetryStorm(t *testing.T) {
+	var mu sync.Mutex
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits++
+		mu.Unlock()
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprintln(w, `{"error_code":"UNAVAILABLE","message":"down"}`)
+	}))
+	defer server.Close()
+
+	// Every request fails and asks for 5 retries; only 10% may be granted.
+	budget := NewRatioRetryBudget(0.1, 0)
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithRetryBudget(budget))
+	var result ChainIdResponse
+	for i := 0; i < 100; i++ {
+		if err := client.GetMethodWith(context.Background(), "/v1/chains/chain_id", &result, WithRequestRetries(5)); err == nil {
+			t.Fatal("Expected every request to fail")
+		}
+	}
+
+	stats := budget.Stats()
+	if stats.Requests != 100 {
+		t.Errorf("Expected only the 100 first attempts to be recorded, got %d", stats.Requests)
+	}
+	if stats.Retries > 10 {
+		t.Errorf("Expected at most 10 retries, got %d", stats.Retries)
+	}
+	if hits > 110 {
+		t.Errorf("Expected at most 110 requests to reach the node, got %d", hits)
+	}
+}
@@ -1,6 +1,7 @@
 package onemoney
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"strings"
 
@@ -15,18 +16,33 @@ type Signature struct {
 	V uint64 `json:"v"`
 }
 
-func (client *Client) SignMessage(msg interface{}, privateKey string) (*Signature, error) {
-	privateKey = strings.TrimPrefix(privateKey, "0x")
-	encoded, err := rlp.EncodeToBytes(msg)
-	if err != nil {
-		return nil, fmt.Errorf("encode message: %w", err)
-	}
-	key, err := crypto.HexToECDSA(privateKey)
+// Signer produces signatures over payload digests. Implement it to back signing
+// with a KMS or HSM instead of a raw private key.
+type Signer interface {
+	// Sign signs a 32-byte Keccak256 digest of the RLP-encoded payload.
+	Sign(digest []byte) (*Signature, error)
+	// Address returns the address whose key produces the signatures.
+	Address() common.Address
+}
+
+// LocalSigner is a Signer backed by an in-memory ECDSA private key.
+type LocalSigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewLocalSigner creates a LocalSigner from a hex private key, with or without 0x prefix.
+func NewLocalSigner(privateKey string) (*LocalSigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid private key: %w", err)
 	}
-	hash := crypto.Keccak256(encoded)
-	signature, err := crypto.Sign(hash, key)
+	return &LocalSigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}, nil
+}
+
+// Sign implements Signer.
+func (s *LocalSigner) Sign(digest []byte) (*Signature, error) {
+	signature, err := crypto.Sign(digest, s.key)
 	if err != nil {
 		return nil, fmt.Errorf("sign message: %w", err)
 	}
@@ -36,3 +52,35 @@ func (client *Client) SignMessage(msg interface{}, privateKey string) (*Signatur
 		V: uint64(signature[64]),
 	}, nil
 }
+
+// Address implements Signer.
+func (s *LocalSigner) Address() common.Address {
+	return s.address
+}
+
+func (client *Client) SignMessage(msg interface{}, privateKey string) (*Signature, error) {
+	signer, err := NewLocalSigner(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return SignMessageWithSigner(msg, signer)
+}
+
+// SignMessageWithSigner signs msg the same way as SignMessage, delegating the
+// ECDSA operation to signer.
+func SignMessageWithSigner(msg interface{}, signer Signer) (*Signature, error) {
+	digest, err := messageDigest(msg)
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(digest)
+}
+
+// messageDigest returns the Keccak256 hash of the RLP encoding of msg.
+func messageDigest(msg interface{}) ([]byte, error) {
+	encoded, err := rlp.EncodeToBytes(msg)
+	if err != nil {
+		return nil, fmt.Errorf("encode message: %w", err)
+	}
+	return crypto.Keccak256(encoded), nil
+}
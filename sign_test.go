@@ -0,0 +1,65 @@
+package onemoney
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestSignMessageWithSigner(t *testing.T) {
+	payload := PaymentPayload{
+		RecentCheckpoint: 100,
+		ChainID:          1212101,
+		Nonce:            1,
+		Recipient:        common.HexToAddress("0x0000000000000000000000000000000000000002"),
+		Value:            NewTokenValue(big.NewInt(4025)),
+		Token:            common.HexToAddress("0x0000000000000000000000000000000000000003"),
+	}
+
+	signer, err := NewLocalSigner(testPrivateKey)
+	if err != nil {
+		t.Fatalf("NewLocalSigner failed: %v", err)
+	}
+	expectedAddress, err := PrivateKeyToAddress(testPrivateKey)
+	if err != nil {
+		t.Fatalf("PrivateKeyToAddress failed: %v", err)
+	}
+	if signer.Address().Hex() != expectedAddress {
+		t.Errorf("Expected signer address %s, got %s", expectedAddress, signer.Address().Hex())
+	}
+
+	fromKey, err := NewClient().SignMessage(payload, testPrivateKey)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	fromSigner, err := SignMessageWithSigner(payload, signer)
+	if err != nil {
+		t.Fatalf("SignMessageWithSigner failed: %v", err)
+	}
+	if *fromKey != *fromSigner {
+		t.Errorf("Expected identical signatures, got %+v and %+v", fromKey, fromSigner)
+	}
+
+	// The signature must recover to the signer's address.
+	digest, err := messageDigest(payload)
+	if err != nil {
+		t.Fatalf("messageDigest failed: %v", err)
+	}
+	sig := append(common.HexToHash(fromSigner.R).Bytes(), common.HexToHash(fromSigner.S).Bytes()...)
+	sig = append(sig, byte(fromSigner.V))
+	pub, err := crypto.SigToPub(digest, sig)
+	if err != nil {
+		t.Fatalf("SigToPub failed: %v", err)
+	}
+	if crypto.PubkeyToAddress(*pub) != signer.Address() {
+		t.Errorf("Signature recovers to %s, expected %s", crypto.PubkeyToAddress(*pub).Hex(), signer.Address().Hex())
+	}
+}
+
+func TestNewLocalSigner_InvalidKey(t *testing.T) {
+	if _, err := NewLocalSigner("0xnothex"); err == nil {
+		t.Error("Expected NewLocalSigner to reject an invalid key")
+	}
+}
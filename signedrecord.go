@@ -0,0 +1,177 @@
+package onemoney
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrSignedRecordNotFound is returned by SignedTransactionStore.Load when no
+// record has the requested ID.
+var ErrSignedRecordNotFound = errors.New("signed transaction record not found")
+
+// SignedTransactionRecord is an audit entry for one signed transaction,
+// including its signature.
+type SignedTransactionRecord struct {
+	ID            string    `json:"id"`
+	Timestamp     int64     `json:"timestamp"`
+	OperationType string    `json:"operation_type"`
+	SignerAddress string    `json:"signer_address"`
+	PayloadHash   string    `json:"payload_hash"`
+	Signature     Signature `json:"signature"`
+	TxHash        string    `json:"tx_hash"`
+}
+
+// ExportSignedRecord builds the audit record for a signed request such as a
+// *PaymentRequest, i.e. a struct embedding its payload next to a Signature
+// made with SignMessage. The signer address is recovered from the signature,
+// and the ID is a UUIDv5 derived from the payload hash and txHash, so
+// exporting the same transaction twice yields the same ID.
+func ExportSignedRecord(req interface{}, txHash string) (*SignedTransactionRecord, error) {
+	payload, signature, err := splitSignedRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := messageDigest(payload)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := recoverSigner(digest, signature)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedTransactionRecord{
+		ID:            GenerateIdempotencyKey(append(digest, txHash...)),
+		Timestamp:     time.Now().Unix(),
+		OperationType: operationType(payload),
+		SignerAddress: signer.Hex(),
+		PayloadHash:   hexutil.Encode(digest),
+		Signature:     signature,
+		TxHash:        txHash,
+	}, nil
+}
+
+// splitSignedRequest returns the embedded payload and the Signature of req.
+func splitSignedRequest(req interface{}) (interface{}, Signature, error) {
+	v := reflect.ValueOf(req)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, Signature{}, fmt.Errorf("signed request must be a struct, got %T", req)
+	}
+	var payload reflect.Value
+	signature, ok := Signature{}, false
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		switch {
+		case field.Type == reflect.TypeOf(Signature{}):
+			signature, ok = v.Field(i).Interface().(Signature), true
+		case field.Anonymous && field.Type.Kind() == reflect.Struct:
+			payload = v.Field(i)
+		}
+	}
+	if !payload.IsValid() || !ok {
+		return nil, Signature{}, fmt.Errorf("%T does not embed a payload next to a Signature", req)
+	}
+	return payload.Interface(), signature, nil
+}
+
+func recoverSigner(digest []byte, signature Signature) (common.Address, error) {
+	raw := make([]byte, 0, 65)
+	raw = append(raw, common.HexToHash(signature.R).Bytes()...)
+	raw = append(raw, common.HexToHash(signature.S).Bytes()...)
+	raw = append(raw, byte(signature.V))
+	pub, err := crypto.SigToPub(digest, raw)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// operationType names a payload after its EIP-712 type, falling back to the
+// Go type name without its Payload suffix.
+func operationType(payload interface{}) string {
+	if typed, ok := payload.(EIP712Typed); ok {
+		return typed.EIP712Type()
+	}
+	return strings.TrimSuffix(reflect.TypeOf(payload).Name(), "Payload")
+}
+
+// SignedTransactionStore persists SignedTransactionRecords.
+type SignedTransactionStore interface {
+	Save(record *SignedTransactionRecord) error
+	Load(id string) (*SignedTransactionRecord, error)
+}
+
+// FileSignedTransactionStore is a SignedTransactionStore backed by an
+// append-only NDJSON file, one record per line. Records are never rewritten.
+type FileSignedTransactionStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileSignedTransactionStore returns a store writing to path. The file is
+// created on the first Save.
+func NewFileSignedTransactionStore(path string) *FileSignedTransactionStore {
+	return &FileSignedTransactionStore{path: path}
+}
+
+// Save implements SignedTransactionStore.
+func (s *FileSignedTransactionStore) Save(record *SignedTransactionRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("encode signed record: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Load implements SignedTransactionStore. If id was saved more than once the
+// first record is returned.
+func (s *FileSignedTransactionStore) Load(id string) (*SignedTransactionRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrSignedRecordNotFound, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		record := new(SignedTransactionRecord)
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, fmt.Errorf("decode signed record in %s: %w", s.path, err)
+		}
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSignedRecordNotFound, id)
+}
@@ -0,0 +1,81 @@
+package onemoney
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestExportSignedRecord(t *testing.T) {
+	client := newClientInternal("http://localhost")
+	payload := PaymentPayload{
+		RecentCheckpoint: 10,
+		ChainID:          1212101,
+		Nonce:            3,
+		Recipient:        common.HexToAddress("0x2"),
+		Value:            NewTokenValue(big.NewInt(100)),
+		Token:            common.HexToAddress("0x3"),
+	}
+	signature, err := client.SignMessage(payload, testPrivateKey)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	req := &PaymentRequest{PaymentPayload: payload, Signature: *signature}
+
+	record, err := ExportSignedRecord(req, "0xabc")
+	if err != nil {
+		t.Fatalf("ExportSignedRecord failed: %v", err)
+	}
+	signer, _ := PrivateKeyToAddress(testPrivateKey)
+	if record.SignerAddress != common.HexToAddress(signer).Hex() {
+		t.Errorf("SignerAddress = %s, want %s", record.SignerAddress, signer)
+	}
+	if record.OperationType != "Payment" || record.TxHash != "0xabc" || record.Signature != *signature {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	again, _ := ExportSignedRecord(*req, "0xabc")
+	if again.ID != record.ID {
+		t.Errorf("ID is not deterministic: %s != %s", again.ID, record.ID)
+	}
+	if other, _ := ExportSignedRecord(req, "0xdef"); other.ID == record.ID {
+		t.Error("different tx hashes produced the same ID")
+	}
+
+	if _, err := ExportSignedRecord(&payload, "0xabc"); err == nil {
+		t.Error("Expected an error for a payload without a signature")
+	}
+}
+
+func TestFileSignedTransactionStore(t *testing.T) {
+	store := NewFileSignedTransactionStore(filepath.Join(t.TempDir(), "signed.ndjson"))
+	if _, err := store.Load("missing"); !errors.Is(err, ErrSignedRecordNotFound) {
+		t.Errorf("Expected ErrSignedRecordNotFound before any Save, got %v", err)
+	}
+
+	records := []*SignedTransactionRecord{
+		{ID: "a", Timestamp: 1, OperationType: "Payment", SignerAddress: "0x1", PayloadHash: "0x11",
+			Signature: Signature{R: "0x1", S: "0x2", V: 1}, TxHash: "0xaa"},
+		{ID: "b", Timestamp: 2, OperationType: "TokenMint", SignerAddress: "0x2", PayloadHash: "0x22",
+			Signature: Signature{R: "0x3", S: "0x4", V: 0}, TxHash: "0xbb"},
+	}
+	for _, record := range records {
+		if err := store.Save(record); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+	}
+	for _, want := range records {
+		got, err := store.Load(want.ID)
+		if err != nil {
+			t.Fatalf("Load(%s) failed: %v", want.ID, err)
+		}
+		if *got != *want {
+			t.Errorf("Load(%s) = %+v, want %+v", want.ID, got, want)
+		}
+	}
+	if _, err := store.Load("missing"); !errors.Is(err, ErrSignedRecordNotFound) {
+		t.Errorf("Expected ErrSignedRecordNotFound, got %v", err)
+	}
+}
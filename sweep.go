@@ -0,0 +1,119 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SweepResult reports what happened to one source wallet during SweepTokens.
+// Amount and TxHash are empty when the wallet was skipped or failed.
+type SweepResult struct {
+	WalletAddress string
+	Amount        string
+	TxHash        string
+	Error         error
+}
+
+type sweepConfig struct {
+	minSweep *big.Int
+	interval time.Duration
+}
+
+// SweepOption configures SweepTokens.
+type SweepOption func(*sweepConfig)
+
+// WithMinSweep skips wallets whose balance is below min. A nil min keeps the
+// default of 1.
+func WithMinSweep(min *big.Int) SweepOption {
+	return func(c *sweepConfig) {
+		if min != nil {
+			c.minSweep = min
+		}
+	}
+}
+
+// WithSweepRate limits SweepTokens to tps wallets per second.
+func WithSweepRate(tps int) SweepOption {
+	return func(c *sweepConfig) {
+		if tps > 0 {
+			c.interval = time.Second / time.Duration(tps)
+		}
+	}
+}
+
+// SweepTokens moves the full token balance of every source wallet to destination.
+// Wallets with a zero balance, or below WithMinSweep, are skipped. Failures are
+// reported per wallet in the results; the returned error is only set when the
+// sweep could not start or ctx was cancelled.
+func SweepTokens(ctx context.Context, client *Client, sources []*Wallet, destination string, tokenAddress string, opts ...SweepOption) ([]SweepResult, error) {
+	cfg := sweepConfig{minSweep: big.NewInt(1)}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	chain, err := client.GetChainId(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get chain id: %w", err)
+	}
+
+	results := make([]SweepResult, 0, len(sources))
+	for i, source := range sources {
+		if i > 0 && cfg.interval > 0 {
+			select {
+			case <-ctx.Done():
+				return results, ctx.Err()
+			case <-time.After(cfg.interval):
+			}
+		}
+		result := SweepResult{WalletAddress: source.Address}
+		result.Amount, result.TxHash, result.Error = sweepWallet(ctx, client, uint64(chain.ChainId), source, destination, tokenAddress, cfg.minSweep)
+		results = append(results, result)
+		if ctx.Err() != nil {
+			return results, ctx.Err()
+		}
+	}
+	return results, nil
+}
+
+// sweepWallet sends the balance of one wallet, returning empty strings when skipped.
+func sweepWallet(ctx context.Context, client *Client, chainID uint64, source *Wallet, destination, tokenAddress string, minSweep *big.Int) (string, string, error) {
+	account, err := client.GetTokenAccount(ctx, source.Address, tokenAddress)
+	if err != nil {
+		return "", "", fmt.Errorf("get balance: %w", err)
+	}
+	balance, ok := new(big.Int).SetString(account.Balance, 10)
+	if !ok {
+		return "", "", fmt.Errorf("invalid balance %q", account.Balance)
+	}
+	if balance.Sign() == 0 || balance.Cmp(minSweep) < 0 {
+		return "", "", nil
+	}
+	accountNonce, err := client.GetAccountNonce(ctx, source.Address)
+	if err != nil {
+		return "", "", fmt.Errorf("get account nonce: %w", err)
+	}
+	checkpoint, err := client.RecentCheckpoint(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("get checkpoint number: %w", err)
+	}
+	payload := PaymentPayload{
+		RecentCheckpoint: checkpoint,
+		ChainID:          chainID,
+		Nonce:            accountNonce.Nonce,
+		Recipient:        common.HexToAddress(destination),
+		Value:            NewTokenValue(balance),
+		Token:            common.HexToAddress(tokenAddress),
+	}
+	signature, err := client.SignMessage(payload, source.PrivateKey)
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := client.SendPayment(ctx, &PaymentRequest{PaymentPayload: payload, Signature: *signature})
+	if err != nil {
+		return "", "", err
+	}
+	return balance.String(), resp.Hash, nil
+}
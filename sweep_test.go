@@ -0,0 +1,91 @@
+package onemoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSweepTokens(t *testing.T) {
+	keys := []string{
+		testPrivateKey,
+		"0x8f2a55949038a9610f50fb23b5883af3b4ecb3c3bb792cbcefbd1542c692be63",
+		"0xc87509a1c067bbde78beb793e6fa76530b6382a4c0241e5e4a9ec0a0f44dc0d3",
+	}
+	balances := map[string]string{}
+	var sources []*Wallet
+	for i, key := range keys {
+		address, err := PrivateKeyToAddress(key)
+		if err != nil {
+			t.Fatalf("PrivateKeyToAddress failed: %v", err)
+		}
+		sources = append(sources, &Wallet{Address: address, PrivateKey: key})
+		balances[strings.ToLower(address)] = []string{"500", "0", "5"}[i]
+	}
+
+	var mu sync.Mutex
+	var payments []PaymentRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chains/chain_id":
+			fmt.Fprintln(w, `{"chain_id":1212101}`)
+		case "/v1/accounts/token_account":
+			fmt.Fprintf(w, `{"balance":"%s","nonce":0}`, balances[strings.ToLower(r.URL.Query().Get("address"))])
+		case "/v1/accounts/nonce":
+			fmt.Fprintln(w, `{"nonce":4}`)
+		case "/v1/checkpoints/number":
+			fmt.Fprintln(w, `{"number":77}`)
+		case "/v1/transactions/payment":
+			var req PaymentRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			mu.Lock()
+			payments = append(payments, req)
+			mu.Unlock()
+			fmt.Fprintln(w, `{"hash":"0xsweep"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	destination := "0x00000000000000000000000000000000000000dd"
+	token := "0x0000000000000000000000000000000000000003"
+	results, err := SweepTokens(context.Background(), client, sources, destination, token,
+		WithMinSweep(big.NewInt(10)), WithSweepRate(1000))
+	if err != nil {
+		t.Fatalf("SweepTokens failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Amount != "500" || results[0].TxHash != "0xsweep" || results[0].Error != nil {
+		t.Errorf("Expected first wallet to be swept, got %+v", results[0])
+	}
+	for _, skipped := range results[1:] {
+		if skipped.TxHash != "" || skipped.Error != nil {
+			t.Errorf("Expected wallet %s to be skipped, got %+v", skipped.WalletAddress, skipped)
+		}
+	}
+	if len(payments) != 1 {
+		t.Fatalf("Expected 1 payment, got %d", len(payments))
+	}
+	if payments[0].Value.Int().Int64() != 500 || payments[0].Nonce != 4 || !strings.EqualFold(payments[0].Recipient.Hex(), destination) {
+		t.Errorf("Unexpected payment payload: %+v", payments[0].PaymentPayload)
+	}
+}
+
+func TestWithMinSweep_Nil(t *testing.T) {
+	cfg := sweepConfig{minSweep: big.NewInt(1)}
+	WithMinSweep(nil)(&cfg)
+	if cfg.minSweep == nil || cfg.minSweep.Int64() != 1 {
+		t.Errorf("WithMinSweep(nil) should keep the default of 1, got %v", cfg.minSweep)
+	}
+}
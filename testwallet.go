@@ -0,0 +1,43 @@
+package onemoney
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// GenerateTestWallet derives a wallet whose private key is sha256(seed), for
+// tests and local networks. The same seed always yields the same wallet. Never
+// hold real funds with it: anyone who knows the seed knows the key.
+func GenerateTestWallet(seed string) *Wallet {
+	sum := sha256.Sum256([]byte(seed))
+	key, err := crypto.ToECDSA(sum[:])
+	for err != nil {
+		// sha256 output is outside the curve order with negligible probability;
+		// rehash rather than fail.
+		sum = sha256.Sum256(sum[:])
+		key, err = crypto.ToECDSA(sum[:])
+	}
+	return &Wallet{
+		PrivateKey: hexutil.Encode(crypto.FromECDSA(key)),
+		Address:    crypto.PubkeyToAddress(key.PublicKey).Hex(),
+	}
+}
+
+// GenerateTestWallets returns n wallets seeded with prefix followed by 0..n-1.
+func GenerateTestWallets(n int, prefix string) []*Wallet {
+	wallets := make([]*Wallet, n)
+	for i := range wallets {
+		wallets[i] = GenerateTestWallet(fmt.Sprintf("%s%d", prefix, i))
+	}
+	return wallets
+}
+
+// GenerateTestTokenAddress derives a deterministic placeholder token address
+// from symbol, for local networks where TestTokenAddress is not deployed.
+func GenerateTestTokenAddress(symbol string) string {
+	return common.BytesToAddress(crypto.Keccak256([]byte("1money-test-token:" + symbol))).Hex()
+}
@@ -0,0 +1,77 @@
+package onemoney
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestGenerateTestWallet(t *testing.T) {
+	a := GenerateTestWallet("operator")
+	b := GenerateTestWallet("operator")
+	if a.Address != b.Address || a.PrivateKey != b.PrivateKey {
+		t.Error("same seed produced different wallets")
+	}
+	sum := sha256.Sum256([]byte("operator"))
+	if a.PrivateKey != "0x"+hex.EncodeToString(sum[:]) {
+		t.Errorf("PrivateKey = %s, want sha256(seed)", a.PrivateKey)
+	}
+	if addr, err := PrivateKeyToAddress(a.PrivateKey); err != nil || addr != a.Address {
+		t.Errorf("PrivateKeyToAddress = %s, %v; want %s", addr, err, a.Address)
+	}
+	if GenerateTestWallet("other").Address == a.Address {
+		t.Error("different seeds produced the same wallet")
+	}
+}
+
+func TestGenerateTestWallets(t *testing.T) {
+	wallets := GenerateTestWallets(3, "user")
+	if len(wallets) != 3 {
+		t.Fatalf("got %d wallets", len(wallets))
+	}
+	seen := make(map[string]bool)
+	for i, w := range wallets {
+		if seen[w.Address] {
+			t.Errorf("duplicate wallet at %d", i)
+		}
+		seen[w.Address] = true
+	}
+	if wallets[1].Address != GenerateTestWallet("user1").Address {
+		t.Error("wallet 1 should be seeded with \"user1\"")
+	}
+}
+
+func TestGenerateTestWallet_Sign(t *testing.T) {
+	w := GenerateTestWallet("signer")
+	payload := PaymentPayload{ChainID: 1212101, Nonce: 1, Value: NewTokenValue(big.NewInt(5)), Token: common.HexToAddress(GenerateTestTokenAddress("USDA"))}
+	sig, err := w.Sign(payload)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	viaClient, _ := NewTestClient().SignMessage(payload, w.PrivateKey)
+	if *sig != *viaClient {
+		t.Errorf("Sign = %+v, SignMessage = %+v", sig, viaClient)
+	}
+	digest, _ := messageDigest(payload)
+	raw := append(append(common.HexToHash(sig.R).Bytes(), common.HexToHash(sig.S).Bytes()...), byte(sig.V))
+	pub, err := crypto.SigToPub(digest, raw)
+	if err != nil || crypto.PubkeyToAddress(*pub).Hex() != w.Address {
+		t.Errorf("signature does not recover to the wallet address")
+	}
+}
+
+func TestGenerateTestTokenAddress(t *testing.T) {
+	if GenerateTestTokenAddress("USDA") != GenerateTestTokenAddress("USDA") {
+		t.Error("GenerateTestTokenAddress is not deterministic")
+	}
+	if GenerateTestTokenAddress("USDA") == GenerateTestTokenAddress("USDB") {
+		t.Error("different symbols produced the same address")
+	}
+	if !common.IsHexAddress(GenerateTestTokenAddress("USDA")) {
+		t.Errorf("GenerateTestTokenAddress returned %q", GenerateTestTokenAddress("USDA"))
+	}
+}
@@ -0,0 +1,51 @@
+package onemoney
+
+import (
+	"context"
+	"time"
+)
+
+type escalatingTimeout struct {
+	initial time.Duration
+	max     time.Duration
+	factor  float64
+}
+
+// WithEscalatingTimeout gives each attempt of a polling loop its own deadline,
+// starting at initial and growing by factor per attempt up to max, so that a
+// fast node answers quickly while a loaded one still gets a longer chance on
+// later attempts. It applies to the polls of WaitForReceipt and
+// WaitForConfirmations and to the *AndWait helpers built on them. The
+// WithTimeout limit still caps every request.
+func WithEscalatingTimeout(initial, max time.Duration, factor float64) ClientOption {
+	return func(c *Client) {
+		if factor < 1 {
+			factor = 1
+		}
+		if max < initial {
+			max = initial
+		}
+		c.escalatingTimeout = &escalatingTimeout{initial: initial, max: max, factor: factor}
+	}
+}
+
+// timeout returns min(initial * factor^attempt, max).
+func (e *escalatingTimeout) timeout(attempt int) time.Duration {
+	d := float64(e.initial)
+	for i := 0; i < attempt && d < float64(e.max); i++ {
+		d *= e.factor
+	}
+	if d > float64(e.max) {
+		return e.max
+	}
+	return time.Duration(d)
+}
+
+// attemptContext returns the context for the given attempt of a retry loop,
+// bounded by the escalating timeout when one is set.
+func (client *Client) attemptContext(ctx context.Context, attempt int) (context.Context, context.CancelFunc) {
+	if client.escalatingTimeout == nil {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, client.escalatingTimeout.timeout(attempt))
+}
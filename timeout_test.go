@@ -0,0 +1,58 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEscalatingTimeout(t *testing.T) {
+	client := newClientInternal("http://unused.invalid", WithEscalatingTimeout(100*time.Millisecond, time.Second, 2))
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{50, time.Second},
+	}
+	for _, tt := range tests {
+		if got := client.escalatingTimeout.timeout(tt.attempt); got != tt.want {
+			t.Errorf("timeout(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestEscalatingTimeout_WaitForReceipt(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls.Add(1)
+		select {
+		case <-time.After(60 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		fmt.Fprintln(w, `{"transaction_hash":"0xabc","success":true}`)
+	}))
+	defer server.Close()
+
+	// Attempts get 20ms, 40ms and then 80ms, so only the third outlasts the node.
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithEscalatingTimeout(20*time.Millisecond, time.Second, 2))
+	receipt, err := client.WaitForReceipt(context.Background(), "0xabc", WaitOpts{Interval: time.Millisecond, Multiplier: 1, MaxRetries: 5})
+	if err != nil {
+		t.Fatalf("WaitForReceipt failed: %v", err)
+	}
+	if !receipt.Success {
+		t.Errorf("Unexpected receipt: %+v", receipt)
+	}
+	if got := polls.Load(); got != 3 {
+		t.Errorf("Expected 3 polls, got %d", got)
+	}
+}
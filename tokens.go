@@ -2,14 +2,25 @@ package onemoney
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"net/url"
+	"sort"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// ErrAuthoritiesRemain is returned by ConfirmRevocation when a token still has
+// authorities assigned.
+var ErrAuthoritiesRemain = errors.New("token authorities remain")
+
+// ErrTokenTransferLocked is returned by SendPaymentAndWait when the token has
+// a transfer lock in place.
+var ErrTokenTransferLocked = errors.New("token transfers are locked")
+
 type TokenIssuePayload struct {
 	RecentCheckpoint uint64         `json:"recent_checkpoint"`
 	ChainID          uint64         `json:"chain_id"`
@@ -31,6 +42,10 @@ type IssueTokenResponse struct {
 	Token string `json:"token"`
 }
 
+type TokenSymbolAvailableResponse struct {
+	Available bool `json:"available"`
+}
+
 type AdditionalMetadata struct {
 	Key   string `json:"key"`
 	Value string `json:"value"`
@@ -47,6 +62,15 @@ type MinterAuthority struct {
 	Minter    string `json:"minter"`
 }
 
+// MintAuthorityDetail is a mint authority's allowance and how much of it has
+// been used. Amounts are decimal strings in the token's smallest unit.
+type MintAuthorityDetail struct {
+	Address            string `json:"address"`
+	TotalAllowance     string `json:"total_allowance"`
+	UsedAllowance      string `json:"used_allowance"`
+	RemainingAllowance string `json:"remaining_allowance"`
+}
+
 type TokenInfoResponse struct {
 	Symbol                    string            `json:"symbol"`
 	MasterAuthority           string            `json:"master_authority"`
@@ -107,6 +131,15 @@ const (
 	UnPause PauseActionType = "Unpause"
 )
 
+// PauseEvent records a pause or unpause of a token.
+type PauseEvent struct {
+	TxHash     string          `json:"tx_hash"`
+	Action     PauseActionType `json:"action"`
+	By         string          `json:"by"`
+	Checkpoint uint64          `json:"checkpoint"`
+	Timestamp  int64           `json:"timestamp"`
+}
+
 type ManageListActionType string
 
 const (
@@ -122,7 +155,7 @@ type TokenAuthorityPayload struct {
 	AuthorityType    AuthorityType   `json:"authority_type"`
 	AuthorityAddress common.Address  `json:"authority_address"`
 	Token            common.Address  `json:"token"`
-	Value            *big.Int        `json:"value"`
+	Value            TokenValue      `json:"value"`
 }
 
 type TokenAuthorityRequest struct {
@@ -139,7 +172,7 @@ type TokenMintPayload struct {
 	ChainID          uint64         `json:"chain_id"`
 	Nonce            uint64         `json:"nonce"`
 	Recipient        common.Address `json:"recipient"`
-	Value            *big.Int       `json:"value"`
+	Value            TokenValue     `json:"value"`
 	Token            common.Address `json:"token"`
 }
 
@@ -157,7 +190,7 @@ type TokenBurnPayload struct {
 	ChainID          uint64         `json:"chain_id"`
 	Nonce            uint64         `json:"nonce"`
 	Recipient        common.Address `json:"recipient"`
-	Value            *big.Int       `json:"value"`
+	Value            TokenValue     `json:"value"`
 	Token            common.Address `json:"token"`
 }
 
@@ -188,6 +221,22 @@ type SetTokenManageListResponse struct {
 	Hash string `json:"hash"`
 }
 
+// BatchManageListPayload adds or removes many addresses from a token's
+// blacklist or whitelist in a single transaction.
+type BatchManageListPayload struct {
+	RecentCheckpoint uint64               `json:"recent_checkpoint"`
+	ChainID          uint64               `json:"chain_id"`
+	Nonce            uint64               `json:"nonce"`
+	Action           ManageListActionType `json:"action"`
+	Addresses        []common.Address     `json:"addresses"`
+	Token            common.Address       `json:"token"`
+}
+
+type BatchSetTokenManageListRequest struct {
+	BatchManageListPayload
+	Signature Signature `json:"signature"`
+}
+
 type PauseTokenPayload struct {
 	RecentCheckpoint uint64          `json:"recent_checkpoint"`
 	ChainID          uint64          `json:"chain_id"`
@@ -205,11 +254,147 @@ type PauseTokenResponse struct {
 	Hash string `json:"hash"`
 }
 
+type SetVelocityLimitPayload struct {
+	RecentCheckpoint uint64         `json:"recent_checkpoint"`
+	ChainID          uint64         `json:"chain_id"`
+	Nonce            uint64         `json:"nonce"`
+	Token            common.Address `json:"token"`
+	WindowSeconds    uint64         `json:"window_seconds"`
+	MaxValue         TokenValue     `json:"max_value"`
+}
+
+type SetVelocityLimitRequest struct {
+	SetVelocityLimitPayload
+	Signature Signature `json:"signature"`
+}
+
+type SetVelocityLimitResponse struct {
+	Hash string `json:"hash"`
+}
+
+// RevokeAllAuthoritiesPayload removes every authority from a token in one
+// transaction, typically when decommissioning it.
+type RevokeAllAuthoritiesPayload struct {
+	RecentCheckpoint uint64         `json:"recent_checkpoint"`
+	ChainID          uint64         `json:"chain_id"`
+	Nonce            uint64         `json:"nonce"`
+	Token            common.Address `json:"token"`
+}
+
+type RevokeAllAuthoritiesRequest struct {
+	RevokeAllAuthoritiesPayload
+	Signature Signature `json:"signature"`
+}
+
+type RevokeAllAuthoritiesResponse struct {
+	Hash string `json:"hash"`
+}
+
+// RevokeEntry names one authority to remove in a BatchRevokePayload.
+type RevokeEntry struct {
+	AuthorityType    AuthorityType  `json:"authority_type"`
+	AuthorityAddress common.Address `json:"authority_address"`
+}
+
+// BatchRevokePayload removes several authorities, of any mix of types, from a
+// token in one transaction.
+type BatchRevokePayload struct {
+	RecentCheckpoint uint64         `json:"recent_checkpoint"`
+	ChainID          uint64         `json:"chain_id"`
+	Nonce            uint64         `json:"nonce"`
+	Token            common.Address `json:"token"`
+	Revocations      []RevokeEntry  `json:"revocations"`
+}
+
+type BatchRevokeRequest struct {
+	BatchRevokePayload
+	Signature Signature `json:"signature"`
+}
+
+type BatchRevokeResponse struct {
+	Hash string `json:"hash"`
+}
+
+// SetTransferLockPayload locks or unlocks transfers of a token, for example
+// during a migration window. UnlockAt optionally names the checkpoint at which
+// a lock lifts by itself.
+type SetTransferLockPayload struct {
+	RecentCheckpoint uint64         `json:"recent_checkpoint"`
+	ChainID          uint64         `json:"chain_id"`
+	Nonce            uint64         `json:"nonce"`
+	Token            common.Address `json:"token"`
+	Locked           bool           `json:"locked"`
+	UnlockAt         *uint64        `json:"unlock_at,omitempty" rlp:"optional"`
+}
+
+type SetTransferLockRequest struct {
+	SetTransferLockPayload
+	Signature Signature `json:"signature"`
+}
+
+type SetTransferLockResponse struct {
+	Hash string `json:"hash"`
+}
+
+type transferLockResponse struct {
+	Locked   bool    `json:"locked"`
+	UnlockAt *uint64 `json:"unlock_at"`
+}
+
+// VelocityLimit is the maximum value of a token that may move within a rolling window.
+type VelocityLimit struct {
+	Token         string `json:"token"`
+	WindowSeconds uint64 `json:"window_seconds"`
+	MaxValue      string `json:"max_value"`
+	// UsedValue is the value already moved in the current window.
+	UsedValue string `json:"used_value"`
+}
+
 func (client *Client) IssueToken(ctx context.Context, req *IssueTokenRequest) (*IssueTokenResponse, error) {
 	result := new(IssueTokenResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/tokens/issue", req, result)
 }
 
+// CheckTokenSymbolAvailable reports whether symbol is still free to issue.
+// Call it before signing a TokenIssuePayload so a taken symbol is caught early.
+func (client *Client) CheckTokenSymbolAvailable(ctx context.Context, symbol string) (bool, error) {
+	result := new(TokenSymbolAvailableResponse)
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	if err := client.GetMethod(ctx, fmt.Sprintf("/v1/tokens/symbol_available?%s", params.Encode()), result); err != nil {
+		return false, err
+	}
+	return result.Available, nil
+}
+
+// GetMintAuthorityDetail returns the allowance utilization of authorityAddress
+// on the token. Use it after granting to check the allowance arrived.
+func (client *Client) GetMintAuthorityDetail(ctx context.Context, tokenAddress, authorityAddress string) (*MintAuthorityDetail, error) {
+	result := new(MintAuthorityDetail)
+	params := url.Values{}
+	params.Set("token", tokenAddress)
+	params.Set("authority", authorityAddress)
+	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/tokens/mint_authority_detail?%s", params.Encode()), result)
+}
+
+// TokenBySymbolResponse is the token registered under a symbol.
+type TokenBySymbolResponse struct {
+	Address string `json:"address"`
+	TokenInfoResponse
+}
+
+// GetTokenBySymbol looks up the token issued under symbol. It fails with
+// ErrCodeTokenNotFound (see IsTokenNotFound) when the symbol is free.
+func (client *Client) GetTokenBySymbol(ctx context.Context, symbol string) (*TokenBySymbolResponse, error) {
+	result := new(TokenBySymbolResponse)
+	params := url.Values{}
+	params.Set("symbol", symbol)
+	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/tokens/by_symbol?%s", params.Encode()), result)
+}
+
 func (client *Client) GetTokenMetadata(ctx context.Context, tokenAddress string) (*TokenInfoResponse, error) {
 	result := new(TokenInfoResponse)
 	params := url.Values{}
@@ -219,39 +404,292 @@ func (client *Client) GetTokenMetadata(ctx context.Context, tokenAddress string)
 
 func (client *Client) UpdateTokenMetadata(ctx context.Context, req *UpdateMetadataRequest) (*UpdateMetadataResponse, error) {
 	result := new(UpdateMetadataResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/tokens/update_metadata", req, result)
 }
 
 func (client *Client) GrantTokenAuthority(ctx context.Context, req *TokenAuthorityRequest) (*GrantAuthorityResponse, error) {
 	result := new(GrantAuthorityResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/tokens/grant_authority", req, result)
 }
 
 func (client *Client) MintToken(ctx context.Context, req *MintTokenRequest) (*MintTokenResponse, error) {
 	result := new(MintTokenResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
+	if err := client.validateAmount(ctx, req.Token, req.Value.Int()); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/tokens/mint", req, result)
 }
 
 func (client *Client) BurnToken(ctx context.Context, req *BurnTokenRequest) (*BurnTokenResponse, error) {
 	result := new(BurnTokenResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/tokens/burn", req, result)
 }
 
 func (client *Client) SetTokenBlacklist(ctx context.Context, req *SetTokenManageListRequest) (*SetTokenManageListResponse, error) {
 	result := new(SetTokenManageListResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/tokens/manage_blacklist", req, result)
 }
 
 func (client *Client) SetTokenWhitelist(ctx context.Context, req *SetTokenManageListRequest) (*SetTokenManageListResponse, error) {
 	result := new(SetTokenManageListResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/tokens/manage_whitelist", req, result)
 }
 
+// BatchSetTokenBlacklist applies req.Action to every address in req.Addresses
+// on the token's blacklist.
+func (client *Client) BatchSetTokenBlacklist(ctx context.Context, req *BatchSetTokenManageListRequest) (*SetTokenManageListResponse, error) {
+	return client.batchSetTokenManageList(ctx, "/v1/tokens/manage_blacklist_batch", req)
+}
+
+// BatchSetTokenWhitelist applies req.Action to every address in req.Addresses
+// on the token's whitelist.
+func (client *Client) BatchSetTokenWhitelist(ctx context.Context, req *BatchSetTokenManageListRequest) (*SetTokenManageListResponse, error) {
+	return client.batchSetTokenManageList(ctx, "/v1/tokens/manage_whitelist_batch", req)
+}
+
+func (client *Client) batchSetTokenManageList(ctx context.Context, path string, req *BatchSetTokenManageListRequest) (*SetTokenManageListResponse, error) {
+	result := new(SetTokenManageListResponse)
+	if len(req.Addresses) == 0 {
+		return result, fmt.Errorf("batch manage list: no addresses")
+	}
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
+	return result, client.PostMethod(ctx, path, req, result)
+}
+
 func (client *Client) PauseToken(ctx context.Context, req *PauseTokenRequest) (*PauseTokenResponse, error) {
 	result := new(PauseTokenResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/tokens/pause", req, result)
 }
 
+func (client *Client) SetTokenVelocityLimit(ctx context.Context, req *SetVelocityLimitRequest) (*SetVelocityLimitResponse, error) {
+	result := new(SetVelocityLimitResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
+	return result, client.PostMethod(ctx, "/v1/tokens/set_velocity_limit", req, result)
+}
+
+func (client *Client) GetTokenVelocityLimit(ctx context.Context, tokenAddress string) (*VelocityLimit, error) {
+	result := new(VelocityLimit)
+	params := url.Values{}
+	params.Set("token", tokenAddress)
+	return result, client.GetMethod(ctx, fmt.Sprintf("/v1/tokens/velocity_limit?%s", params.Encode()), result)
+}
+
+// ExceedsVelocityLimit reports whether moving value of the token now would push
+// the current window past its velocity limit. A token without a limit (empty or
+// zero MaxValue) never exceeds it. A warning is logged when it would.
+func (client *Client) ExceedsVelocityLimit(ctx context.Context, tokenAddress string, value *big.Int) (bool, error) {
+	limit, err := client.GetTokenVelocityLimit(ctx, tokenAddress)
+	if err != nil {
+		return false, err
+	}
+	max, ok := new(big.Int).SetString(limit.MaxValue, 10)
+	if !ok || max.Sign() == 0 {
+		return false, nil
+	}
+	used, ok := new(big.Int).SetString(limit.UsedValue, 10)
+	if !ok {
+		used = new(big.Int)
+	}
+	if new(big.Int).Add(used, value).Cmp(max) <= 0 {
+		return false, nil
+	}
+	if client.logEnabled(LogLevelWarn) {
+		client.logger.Warnf("Moving %s of token %s would exceed its velocity limit: %s of %s already used in a %ds window",
+			value, tokenAddress, used, max, limit.WindowSeconds)
+	}
+	return true, nil
+}
+
+// RevokeAllAuthorities posts to its own endpoint rather than the batch one: the
+// signature covers RevokeAllAuthoritiesPayload, which names no authorities, so
+// it cannot be re-sent as a BatchRevokePayload without re-signing. Use
+// BatchRevokeTokenAuthorities to revoke a chosen set of authorities.
+func (client *Client) RevokeAllAuthorities(ctx context.Context, req *RevokeAllAuthoritiesRequest) (*RevokeAllAuthoritiesResponse, error) {
+	result := new(RevokeAllAuthoritiesResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
+	return result, client.PostMethod(ctx, "/v1/tokens/revoke_all_authorities", req, result)
+}
+
+// BatchRevokeTokenAuthorities submits one transaction revoking every entry in
+// req.Revocations. Whether a partially invalid batch is rejected as a whole is
+// up to the node; check the outcome with ConfirmRevocation.
+func (client *Client) BatchRevokeTokenAuthorities(ctx context.Context, req *BatchRevokeRequest) (*BatchRevokeResponse, error) {
+	result := new(BatchRevokeResponse)
+	if len(req.Revocations) == 0 {
+		return result, fmt.Errorf("batch revoke: no revocations")
+	}
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
+	return result, client.PostMethod(ctx, "/v1/tokens/batch_revoke", req, result)
+}
+
+func (client *Client) SetTokenTransferLock(ctx context.Context, req *SetTransferLockRequest) (*SetTransferLockResponse, error) {
+	result := new(SetTransferLockResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
+	return result, client.PostMethod(ctx, "/v1/tokens/set_transfer_lock", req, result)
+}
+
+// GetTokenTransferLock reports whether transfers of the token are locked and,
+// if the lock has one, the checkpoint at which it lifts.
+func (client *Client) GetTokenTransferLock(ctx context.Context, tokenAddress string) (bool, *uint64, error) {
+	result := new(transferLockResponse)
+	params := url.Values{}
+	params.Set("token", tokenAddress)
+	if err := client.GetMethod(ctx, fmt.Sprintf("/v1/tokens/transfer_lock?%s", params.Encode()), result); err != nil {
+		return false, nil, err
+	}
+	return result.Locked, result.UnlockAt, nil
+}
+
+// WithTransferLockCheck makes SendPaymentAndWait read the token's transfer
+// lock before submitting and refuse to pay while it is locked. Only an
+// explicit locked response blocks; if the lock cannot be read, for example
+// because the node has no transfer locks, the payment goes ahead.
+func WithTransferLockCheck() ClientOption {
+	return func(c *Client) {
+		c.checkTransferLocks = true
+	}
+}
+
+// checkTransferLock returns an error wrapping ErrTokenTransferLocked if
+// WithTransferLockCheck is set and the node reports the token locked.
+func (client *Client) checkTransferLock(ctx context.Context, tokenAddress string) error {
+	if !client.checkTransferLocks {
+		return nil
+	}
+	locked, unlockAt, err := client.GetTokenTransferLock(ctx, tokenAddress)
+	if err != nil {
+		if client.logEnabled(LogLevelWarn) {
+			client.logger.Warnf("Could not read transfer lock for token %s, sending anyway: %v", tokenAddress, err)
+		}
+		return nil
+	}
+	if !locked {
+		return nil
+	}
+	if unlockAt == nil {
+		return fmt.Errorf("%w: token %s has no scheduled unlock", ErrTokenTransferLocked, tokenAddress)
+	}
+	return fmt.Errorf("%w: token %s unlocks at checkpoint %d", ErrTokenTransferLocked, tokenAddress, *unlockAt)
+}
+
+// ConfirmRevocation checks the token's metadata and returns an error wrapping
+// ErrAuthoritiesRemain, naming each authority that is still set. The master
+// authority is not checked because it owns the token.
+func (client *Client) ConfirmRevocation(ctx context.Context, tokenAddress string) error {
+	meta, err := client.GetTokenMetadata(ctx, tokenAddress)
+	if err != nil {
+		return err
+	}
+	var remaining []string
+	if meta.MasterMintBurnAuthority != "" && common.HexToAddress(meta.MasterMintBurnAuthority) != (common.Address{}) {
+		remaining = append(remaining, "master_mint_burn_authority")
+	}
+	if len(meta.MintBurnAuthority) > 0 {
+		remaining = append(remaining, fmt.Sprintf("mint_burn_authorities (%d)", len(meta.MintBurnAuthority)))
+	}
+	if len(meta.PauseAuthorities) > 0 {
+		remaining = append(remaining, fmt.Sprintf("pause_authorities (%d)", len(meta.PauseAuthorities)))
+	}
+	if len(meta.ListAuthorities) > 0 {
+		remaining = append(remaining, fmt.Sprintf("list_authorities (%d)", len(meta.ListAuthorities)))
+	}
+	if len(meta.MetadataUpdateAuthorities) > 0 {
+		remaining = append(remaining, fmt.Sprintf("metadata_update_authorities (%d)", len(meta.MetadataUpdateAuthorities)))
+	}
+	if len(remaining) > 0 {
+		return fmt.Errorf("%w for token %s: %s", ErrAuthoritiesRemain, tokenAddress, strings.Join(remaining, ", "))
+	}
+	return nil
+}
+
+// IsBlacklisted reports whether address is on the token's blacklist.
+// The API has no single-address lookup, so this scans the metadata's BlackList.
+func (client *Client) IsBlacklisted(ctx context.Context, token, address string) (bool, error) {
+	meta, err := client.GetTokenMetadata(ctx, token)
+	if err != nil {
+		return false, err
+	}
+	return containsAddress(meta.BlackList, address), nil
+}
+
+// IsWhitelisted reports whether address is on the token's whitelist.
+// The API has no single-address lookup, so this scans the metadata's WhiteList.
+func (client *Client) IsWhitelisted(ctx context.Context, token, address string) (bool, error) {
+	meta, err := client.GetTokenMetadata(ctx, token)
+	if err != nil {
+		return false, err
+	}
+	return containsAddress(meta.WhiteList, address), nil
+}
+
+// containsAddress reports whether list contains address, ignoring hex case.
+func containsAddress(list []string, address string) bool {
+	target := common.HexToAddress(address)
+	for _, entry := range list {
+		if common.HexToAddress(entry) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTokenPauseHistory returns every pause and unpause of the token, oldest first.
+func (client *Client) GetTokenPauseHistory(ctx context.Context, tokenAddress string) ([]PauseEvent, error) {
+	var result []PauseEvent
+	params := url.Values{}
+	params.Set("token", tokenAddress)
+	if err := client.GetMethod(ctx, fmt.Sprintf("/v1/tokens/pause_history?%s", params.Encode()), &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// IsPausedAtCheckpoint reports whether the token was paused as of checkpoint,
+// i.e. whether the last pause event at or before it was a Pause.
+func (client *Client) IsPausedAtCheckpoint(ctx context.Context, tokenAddress string, checkpoint uint64) (bool, error) {
+	history, err := client.GetTokenPauseHistory(ctx, tokenAddress)
+	if err != nil {
+		return false, err
+	}
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Checkpoint < history[j].Checkpoint })
+	i := sort.Search(len(history), func(i int) bool { return history[i].Checkpoint > checkpoint })
+	if i == 0 {
+		return false, nil
+	}
+	return history[i-1].Action == Pause, nil
+}
+
 // DeriveTokenAccountAddress derives the token account address given the wallet address and mint address.
 //
 // Address is 20 byte, 160 bits. Let's say if we want to support 50 billion
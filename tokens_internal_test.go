@@ -0,0 +1,510 @@
+package onemoney
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestCheckTokenSymbolAvailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/symbol_available" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND", "message":"Endpoint not found"}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		switch r.URL.Query().Get("symbol") {
+		case "FREE":
+			fmt.Fprintln(w, `{"available":true}`)
+		default:
+			fmt.Fprintln(w, `{"available":false}`)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+
+	available, err := client.CheckTokenSymbolAvailable(context.Background(), "FREE")
+	if err != nil {
+		t.Fatalf("CheckTokenSymbolAvailable failed: %v", err)
+	}
+	if !available {
+		t.Error("Expected symbol FREE to be available")
+	}
+
+	available, err = client.CheckTokenSymbolAvailable(context.Background(), "USDA")
+	if err != nil {
+		t.Fatalf("CheckTokenSymbolAvailable failed: %v", err)
+	}
+	if available {
+		t.Error("Expected symbol USDA to be taken")
+	}
+}
+
+func TestGetTokenBySymbol(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/by_symbol" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("symbol") != "USDA" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"TOKEN_NOT_FOUND","message":"no token with this symbol"}`)
+			return
+		}
+		fmt.Fprintln(w, `{"address":"0x0000000000000000000000000000000000000009","symbol":"USDA","decimals":6,
+			"master_authority":"0x0000000000000000000000000000000000000001"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	token, err := client.GetTokenBySymbol(context.Background(), "USDA")
+	if err != nil {
+		t.Fatalf("GetTokenBySymbol failed: %v", err)
+	}
+	if token.Address != "0x0000000000000000000000000000000000000009" || token.Symbol != "USDA" || token.Decimals != 6 {
+		t.Errorf("Unexpected token: %+v", token)
+	}
+
+	if _, err := client.GetTokenBySymbol(context.Background(), "FREE"); !IsTokenNotFound(err) {
+		t.Errorf("Expected TOKEN_NOT_FOUND for a free symbol, got %v", err)
+	}
+}
+
+func TestGetMintAuthorityDetail(t *testing.T) {
+	token := "0x0000000000000000000000000000000000000009"
+	authority := "0x0000000000000000000000000000000000000002"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v1/tokens/mint_authority_detail" || q.Get("token") != token || q.Get("authority") != authority {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND", "message":"Endpoint not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"address":%q,"total_allowance":"1000","used_allowance":"250","remaining_allowance":"750"}`, authority)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	detail, err := client.GetMintAuthorityDetail(context.Background(), token, authority)
+	if err != nil {
+		t.Fatalf("GetMintAuthorityDetail failed: %v", err)
+	}
+	want := MintAuthorityDetail{Address: authority, TotalAllowance: "1000", UsedAllowance: "250", RemainingAllowance: "750"}
+	if *detail != want {
+		t.Errorf("GetMintAuthorityDetail = %+v, want %+v", *detail, want)
+	}
+}
+
+func TestIsPausedAtCheckpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/pause_history" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `[
+			{"tx_hash":"0x01","action":"Pause","by":"0xaa","checkpoint":100,"timestamp":1700000000},
+			{"tx_hash":"0x02","action":"Unpause","by":"0xaa","checkpoint":200,"timestamp":1700000100},
+			{"tx_hash":"0x03","action":"Pause","by":"0xbb","checkpoint":300,"timestamp":1700000200}
+		]`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	history, err := client.GetTokenPauseHistory(context.Background(), "0xtoken")
+	if err != nil {
+		t.Fatalf("GetTokenPauseHistory failed: %v", err)
+	}
+	if len(history) != 3 || history[1].Action != UnPause || history[2].By != "0xbb" {
+		t.Fatalf("Unexpected pause history: %+v", history)
+	}
+
+	tests := []struct {
+		checkpoint uint64
+		want       bool
+	}{
+		{50, false},
+		{100, true},
+		{150, true},
+		{200, false},
+		{299, false},
+		{300, true},
+		{1000, true},
+	}
+	for _, tt := range tests {
+		paused, err := client.IsPausedAtCheckpoint(context.Background(), "0xtoken", tt.checkpoint)
+		if err != nil {
+			t.Fatalf("IsPausedAtCheckpoint(%d) failed: %v", tt.checkpoint, err)
+		}
+		if paused != tt.want {
+			t.Errorf("IsPausedAtCheckpoint(%d) = %v, want %v", tt.checkpoint, paused, tt.want)
+		}
+	}
+}
+
+func TestIsBlacklistedAndWhitelisted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{
+			"symbol":"USDA",
+			"black_list":["0x00000000000000000000000000000000000000aa"],
+			"white_list":["0x00000000000000000000000000000000000000BB"]
+		}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	tests := []struct {
+		name    string
+		check   func(context.Context, string, string) (bool, error)
+		address string
+		want    bool
+	}{
+		{"blacklisted", client.IsBlacklisted, "0x00000000000000000000000000000000000000AA", true},
+		{"not blacklisted", client.IsBlacklisted, "0x00000000000000000000000000000000000000bb", false},
+		{"whitelisted", client.IsWhitelisted, "0x00000000000000000000000000000000000000bb", true},
+		{"not whitelisted", client.IsWhitelisted, "0x00000000000000000000000000000000000000aa", false},
+	}
+	for _, tt := range tests {
+		got, err := tt.check(context.Background(), "0xtoken", tt.address)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSetTokenVelocityLimit(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/set_velocity_limit" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"hash":"0xlimit"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	req := &SetVelocityLimitRequest{
+		SetVelocityLimitPayload: SetVelocityLimitPayload{
+			RecentCheckpoint: 10,
+			ChainID:          1212101,
+			Nonce:            3,
+			Token:            common.HexToAddress("0x0000000000000000000000000000000000000003"),
+			WindowSeconds:    3600,
+			MaxValue:         NewTokenValue(big.NewInt(1000000)),
+		},
+		Signature: Signature{R: "0x1", S: "0x2", V: 1},
+	}
+	result, err := client.SetTokenVelocityLimit(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SetTokenVelocityLimit failed: %v", err)
+	}
+	if result.Hash != "0xlimit" {
+		t.Errorf("Expected hash '0xlimit', got '%s'", result.Hash)
+	}
+	for field, want := range map[string]interface{}{
+		"recent_checkpoint": 10.0,
+		"chain_id":          1212101.0,
+		"nonce":             3.0,
+		"token":             "0x0000000000000000000000000000000000000003",
+		"window_seconds":    3600.0,
+		"max_value":         "1000000",
+	} {
+		if received[field] != want {
+			t.Errorf("Field %s: got %v, want %v", field, received[field], want)
+		}
+	}
+	if _, ok := received["signature"]; !ok {
+		t.Error("Expected signature in request body")
+	}
+}
+
+func TestExceedsVelocityLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/velocity_limit" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"token":"0xtoken","window_seconds":3600,"max_value":"1000","used_value":"900"}`)
+	}))
+	defer server.Close()
+
+	logger := newMockLogger(t)
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithLogger(logger))
+
+	exceeds, err := client.ExceedsVelocityLimit(context.Background(), "0xtoken", big.NewInt(100))
+	if err != nil {
+		t.Fatalf("ExceedsVelocityLimit failed: %v", err)
+	}
+	if exceeds {
+		t.Error("Expected a value filling the window exactly not to exceed the limit")
+	}
+
+	exceeds, err = client.ExceedsVelocityLimit(context.Background(), "0xtoken", big.NewInt(101))
+	if err != nil {
+		t.Fatalf("ExceedsVelocityLimit failed: %v", err)
+	}
+	if !exceeds {
+		t.Error("Expected a value past the window limit to exceed it")
+	}
+	logger.mu.Lock()
+	warnings := len(logger.warnfCalls)
+	logger.mu.Unlock()
+	if warnings != 1 {
+		t.Errorf("Expected 1 warning, got %d", warnings)
+	}
+}
+
+func TestRevokeAllAuthoritiesAndConfirm(t *testing.T) {
+	revoked := false
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/tokens/revoke_all_authorities":
+			if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			revoked = true
+			fmt.Fprintln(w, `{"hash":"0xrevoke"}`)
+		case "/v1/tokens/token_metadata":
+			if revoked {
+				fmt.Fprintln(w, `{"symbol":"OLD","master_authority":"0x0000000000000000000000000000000000000001","mint_burn_authorities":[],"pause_authorities":[]}`)
+				return
+			}
+			fmt.Fprintln(w, `{"symbol":"OLD","master_authority":"0x0000000000000000000000000000000000000001",
+				"master_mint_burn_authority":"0x0000000000000000000000000000000000000002",
+				"pause_authorities":["0x0000000000000000000000000000000000000003"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	ctx := context.Background()
+	token := "0x0000000000000000000000000000000000000009"
+
+	err := client.ConfirmRevocation(ctx, token)
+	if !errors.Is(err, ErrAuthoritiesRemain) {
+		t.Fatalf("Expected ErrAuthoritiesRemain before revocation, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "master_mint_burn_authority") || !strings.Contains(err.Error(), "pause_authorities (1)") {
+		t.Errorf("Error should name the remaining authorities: %v", err)
+	}
+
+	result, err := client.RevokeAllAuthorities(ctx, &RevokeAllAuthoritiesRequest{
+		RevokeAllAuthoritiesPayload: RevokeAllAuthoritiesPayload{
+			RecentCheckpoint: 10,
+			ChainID:          1212101,
+			Nonce:            4,
+			Token:            common.HexToAddress(token),
+		},
+		Signature: Signature{R: "0x1", S: "0x2", V: 1},
+	})
+	if err != nil {
+		t.Fatalf("RevokeAllAuthorities failed: %v", err)
+	}
+	if result.Hash != "0xrevoke" {
+		t.Errorf("Expected hash '0xrevoke', got '%s'", result.Hash)
+	}
+	if received["token"] != token || received["nonce"] != 4.0 {
+		t.Errorf("Unexpected request body: %v", received)
+	}
+
+	if err := client.ConfirmRevocation(ctx, token); err != nil {
+		t.Errorf("ConfirmRevocation after revoke: %v", err)
+	}
+}
+
+func TestBatchSetTokenBlacklist(t *testing.T) {
+	var received BatchSetTokenManageListRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/manage_blacklist_batch" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `{"hash":"0xbatch"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	payload := BatchManageListPayload{
+		RecentCheckpoint: 10,
+		ChainID:          1212101,
+		Nonce:            4,
+		Action:           ManageListActionAdd,
+		Addresses: []common.Address{
+			common.HexToAddress("0x0000000000000000000000000000000000000011"),
+			common.HexToAddress("0x0000000000000000000000000000000000000012"),
+		},
+		Token: common.HexToAddress("0x0000000000000000000000000000000000000003"),
+	}
+	signature, err := SignEIP712(&payload, testPrivateKey, DefaultDomain(payload.ChainID))
+	if err != nil {
+		t.Fatalf("SignEIP712 failed: %v", err)
+	}
+	result, err := client.BatchSetTokenBlacklist(context.Background(), &BatchSetTokenManageListRequest{
+		BatchManageListPayload: payload,
+		Signature:              *signature,
+	})
+	if err != nil {
+		t.Fatalf("BatchSetTokenBlacklist failed: %v", err)
+	}
+	if result.Hash != "0xbatch" {
+		t.Errorf("Expected hash '0xbatch', got '%s'", result.Hash)
+	}
+	if len(received.Addresses) != 2 || received.Addresses[1] != payload.Addresses[1] {
+		t.Errorf("Unexpected addresses sent: %v", received.Addresses)
+	}
+
+	_, err = client.BatchSetTokenWhitelist(context.Background(), &BatchSetTokenManageListRequest{})
+	if err == nil {
+		t.Error("Expected an error for an empty address list")
+	}
+
+	encodeType, err := EIP712EncodeType(payload)
+	if err != nil {
+		t.Fatalf("EIP712EncodeType failed: %v", err)
+	}
+	if want := "BatchTokenManageList(uint64 recent_checkpoint,uint64 chain_id,uint64 nonce,string action,address[] addresses,address token)"; encodeType != want {
+		t.Errorf("encodeType = %s, want %s", encodeType, want)
+	}
+}
+
+func TestBatchRevokeTokenAuthorities(t *testing.T) {
+	var received BatchRevokeRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/batch_revoke" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintln(w, `{"hash":"0xbatchrevoke"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	payload := BatchRevokePayload{
+		RecentCheckpoint: 10,
+		ChainID:          1212101,
+		Nonce:            5,
+		Token:            common.HexToAddress("0x0000000000000000000000000000000000000009"),
+		Revocations: []RevokeEntry{
+			{AuthorityType: AuthorityTypeMintBurnTokens, AuthorityAddress: common.HexToAddress("0x0000000000000000000000000000000000000002")},
+			{AuthorityType: AuthorityTypePause, AuthorityAddress: common.HexToAddress("0x0000000000000000000000000000000000000003")},
+			{AuthorityType: AuthorityTypeManageList, AuthorityAddress: common.HexToAddress("0x0000000000000000000000000000000000000004")},
+		},
+	}
+
+	// The signed RLP encoding must round-trip with the entries intact and in order.
+	encoded, err := rlp.EncodeToBytes(&payload)
+	if err != nil {
+		t.Fatalf("rlp encode failed: %v", err)
+	}
+	var decoded BatchRevokePayload
+	if err := rlp.DecodeBytes(encoded, &decoded); err != nil {
+		t.Fatalf("rlp decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, payload) {
+		t.Errorf("RLP round trip = %+v, want %+v", decoded, payload)
+	}
+
+	signature, err := client.SignMessage(&payload, testPrivateKey)
+	if err != nil {
+		t.Fatalf("SignMessage failed: %v", err)
+	}
+	result, err := client.BatchRevokeTokenAuthorities(context.Background(), &BatchRevokeRequest{
+		BatchRevokePayload: payload,
+		Signature:          *signature,
+	})
+	if err != nil {
+		t.Fatalf("BatchRevokeTokenAuthorities failed: %v", err)
+	}
+	if result.Hash != "0xbatchrevoke" {
+		t.Errorf("Expected hash '0xbatchrevoke', got '%s'", result.Hash)
+	}
+	if !reflect.DeepEqual(received.Revocations, payload.Revocations) {
+		t.Errorf("Revocations sent = %+v, want %+v", received.Revocations, payload.Revocations)
+	}
+
+	if _, err := client.BatchRevokeTokenAuthorities(context.Background(), &BatchRevokeRequest{}); err == nil {
+		t.Error("Expected an error for an empty revocation list")
+	}
+}
+
+func TestSetTokenTransferLock(t *testing.T) {
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/tokens/set_transfer_lock" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		bodies = append(bodies, body)
+		fmt.Fprintln(w, `{"hash":"0xlock"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	unlockAt := uint64(500)
+	for _, payload := range []SetTransferLockPayload{
+		{RecentCheckpoint: 10, ChainID: 1212101, Nonce: 1, Token: common.HexToAddress("0x3"), Locked: true},
+		{RecentCheckpoint: 10, ChainID: 1212101, Nonce: 2, Token: common.HexToAddress("0x3"), Locked: true, UnlockAt: &unlockAt},
+	} {
+		signature, err := client.SignMessage(&payload, testPrivateKey)
+		if err != nil {
+			t.Fatalf("SignMessage failed: %v", err)
+		}
+		result, err := client.SetTokenTransferLock(context.Background(), &SetTransferLockRequest{
+			SetTransferLockPayload: payload,
+			Signature:              *signature,
+		})
+		if err != nil {
+			t.Fatalf("SetTokenTransferLock failed: %v", err)
+		}
+		if result.Hash != "0xlock" {
+			t.Errorf("Expected hash '0xlock', got '%s'", result.Hash)
+		}
+	}
+	if _, ok := bodies[0]["unlock_at"]; ok {
+		t.Errorf("unlock_at should be omitted when unset: %v", bodies[0])
+	}
+	if bodies[1]["unlock_at"] != 500.0 || bodies[1]["locked"] != true {
+		t.Errorf("Unexpected request body: %v", bodies[1])
+	}
+}
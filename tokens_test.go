@@ -181,7 +181,7 @@ func TestGrantMintBurnAuthority(t *testing.T) {
 		AuthorityType:    onemoney.AuthorityTypeMintBurnTokens,
 		AuthorityAddress: common.HexToAddress(onemoney.TestOperatorAddress),
 		Token:            common.HexToAddress(onemoney.TestTokenAddress),
-		Value:            big.NewInt(1500000),
+		Value:            onemoney.NewTokenValue(big.NewInt(1500000)),
 	}
 	signature, err := client.SignMessage(payload, onemoney.TestOperatorPrivateKey)
 	if err != nil {
@@ -227,7 +227,7 @@ func TestGrantMasterMintAuthority(t *testing.T) {
 		AuthorityType:    onemoney.AuthorityTypeMasterMintBurn,
 		AuthorityAddress: common.HexToAddress(onemoney.TestOperatorAddress),
 		Token:            common.HexToAddress(onemoney.TestTokenAddress),
-		Value:            big.NewInt(1500000),
+		Value:            onemoney.NewTokenValue(big.NewInt(1500000)),
 	}
 	signature, err := client.SignMessage(payload, onemoney.TestOperatorPrivateKey)
 	if err != nil {
@@ -273,7 +273,7 @@ func TestGrantMasterUpdateMetadata(t *testing.T) {
 		AuthorityType:    onemoney.AuthorityTypeUpdateMetadata,
 		AuthorityAddress: common.HexToAddress(onemoney.TestOperatorAddress),
 		Token:            common.HexToAddress(onemoney.TestTokenAddress),
-		Value:            big.NewInt(1500000),
+		Value:            onemoney.NewTokenValue(big.NewInt(1500000)),
 	}
 	signature, err := client.SignMessage(payload, onemoney.TestOperatorPrivateKey)
 	if err != nil {
@@ -319,7 +319,7 @@ func TestGrantMasterUpdatePause(t *testing.T) {
 		AuthorityType:    onemoney.AuthorityTypePause,
 		AuthorityAddress: common.HexToAddress(onemoney.TestOperatorAddress),
 		Token:            common.HexToAddress(onemoney.TestTokenAddress),
-		Value:            big.NewInt(1500000),
+		Value:            onemoney.NewTokenValue(big.NewInt(1500000)),
 	}
 	signature, err := client.SignMessage(payload, onemoney.TestOperatorPrivateKey)
 	if err != nil {
@@ -365,7 +365,7 @@ func TestGrantManageListPause(t *testing.T) {
 		AuthorityType:    onemoney.AuthorityTypeManageList,
 		AuthorityAddress: common.HexToAddress(onemoney.TestOperatorAddress),
 		Token:            common.HexToAddress(onemoney.TestTokenAddress),
-		Value:            big.NewInt(1500000),
+		Value:            onemoney.NewTokenValue(big.NewInt(1500000)),
 	}
 	signature, err := client.SignMessage(payload, onemoney.TestOperatorPrivateKey)
 	if err != nil {
@@ -410,7 +410,7 @@ func TestMintToken(t *testing.T) {
 		ChainID:          1212101,
 		Nonce:            nonce,
 		Recipient:        common.HexToAddress(onemoney.TestOperatorAddress),
-		Value:            big.NewInt(150000),
+		Value:            onemoney.NewTokenValue(big.NewInt(150000)),
 		Token:            common.HexToAddress(onemoney.TestTokenAddress),
 	}
 	// Sign the payload
@@ -458,7 +458,7 @@ func TestBurnToken(t *testing.T) {
 		ChainID:          1212101,
 		Nonce:            nonce,
 		Recipient:        common.HexToAddress(onemoney.TestOperatorAddress),
-		Value:            big.NewInt(15000),
+		Value:            onemoney.NewTokenValue(big.NewInt(15000)),
 		Token:            common.HexToAddress(onemoney.TestTokenAddress),
 	}
 	// Sign the payload
@@ -0,0 +1,86 @@
+package onemoney
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TokenValue is a token amount in the token's smallest unit. It marshals to
+// JSON as a quoted decimal string, so that amounts beyond 2^53 survive
+// JavaScript clients, and accepts both strings and numbers when unmarshalling.
+// It RLP-encodes exactly like *big.Int, so signatures are unaffected.
+//
+// The zero TokenValue is unset: it marshals to null and Int returns nil.
+type TokenValue struct {
+	raw *big.Int
+}
+
+// NewTokenValue returns a TokenValue holding a copy of v. A nil v gives an
+// unset value.
+func NewTokenValue(v *big.Int) TokenValue {
+	if v == nil {
+		return TokenValue{}
+	}
+	return TokenValue{raw: new(big.Int).Set(v)}
+}
+
+// Int returns a copy of the amount, or nil if v is unset.
+func (v TokenValue) Int() *big.Int {
+	if v.raw == nil {
+		return nil
+	}
+	return new(big.Int).Set(v.raw)
+}
+
+// String returns the amount in decimal, or "<nil>" if v is unset.
+func (v TokenValue) String() string {
+	return v.raw.String()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (v TokenValue) MarshalJSON() ([]byte, error) {
+	if v.raw == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v.raw.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *TokenValue) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		v.raw = nil
+		return nil
+	}
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+	}
+	n, ok := new(big.Int).SetString(text, 10)
+	if !ok {
+		return fmt.Errorf("invalid token value %s", data)
+	}
+	v.raw = n
+	return nil
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (v TokenValue) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, v.raw)
+}
+
+// DecodeRLP implements rlp.Decoder.
+func (v *TokenValue) DecodeRLP(s *rlp.Stream) error {
+	n, err := s.BigInt()
+	if err != nil {
+		return err
+	}
+	v.raw = n
+	return nil
+}
@@ -0,0 +1,87 @@
+package onemoney
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestTokenValueJSON(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	value := NewTokenValue(huge)
+
+	data, err := json.Marshal(PaymentPayload{Value: value})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !bytes.Contains(data, []byte(`"value":"123456789012345678901234567890"`)) {
+		t.Errorf("expected a quoted decimal value, got %s", data)
+	}
+	var decoded PaymentPayload
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Value.Int().Cmp(huge) != 0 {
+		t.Errorf("round trip gave %s, want %s", decoded.Value, huge)
+	}
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{`"18446744073709551616"`, "18446744073709551616", false},
+		{`18446744073709551616`, "18446744073709551616", false},
+		{`0`, "0", false},
+		{`null`, "<nil>", false},
+		{`"1.5"`, "", true},
+		{`1e3`, "", true},
+		{`"abc"`, "", true},
+	}
+	for _, tt := range tests {
+		var v TokenValue
+		err := json.Unmarshal([]byte(tt.input), &v)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && v.String() != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, want %s", tt.input, v, tt.want)
+		}
+	}
+
+	if data, _ := json.Marshal(TokenValue{}); string(data) != "null" {
+		t.Errorf("unset value marshals to %s, want null", data)
+	}
+}
+
+func TestTokenValueRLP(t *testing.T) {
+	for _, n := range []*big.Int{nil, big.NewInt(0), big.NewInt(4025), new(big.Int).Lsh(big.NewInt(1), 100)} {
+		got, err := rlp.EncodeToBytes(NewTokenValue(n))
+		if err != nil {
+			t.Fatalf("EncodeToBytes(%v) failed: %v", n, err)
+		}
+		want, _ := rlp.EncodeToBytes(n)
+		if !bytes.Equal(got, want) {
+			t.Errorf("TokenValue(%v) encodes as %x, *big.Int as %x", n, got, want)
+		}
+		var decoded TokenValue
+		if err := rlp.DecodeBytes(got, &decoded); err != nil {
+			t.Fatalf("DecodeBytes failed: %v", err)
+		}
+		if n != nil && decoded.Int().Cmp(n) != 0 {
+			t.Errorf("RLP round trip gave %s, want %s", decoded, n)
+		}
+	}
+
+	// NewTokenValue copies its argument.
+	n := big.NewInt(7)
+	v := NewTokenValue(n)
+	n.SetInt64(8)
+	if v.Int().Int64() != 7 {
+		t.Errorf("TokenValue aliases its input: %s", v)
+	}
+}
@@ -64,6 +64,8 @@ type TransactionReceiptResponse struct {
 	TokenAddress     string `json:"token_address"`
 	TransactionHash  string `json:"transaction_hash"`
 	TransactionIndex int    `json:"transaction_index"`
+	// RevertReason explains why the transaction failed when Success is false.
+	RevertReason string `json:"revert_reason"`
 }
 
 func (client *Client) GetTransactionReceipt(ctx context.Context, hash string) (*TransactionReceiptResponse, error) {
@@ -88,12 +90,53 @@ func (client *Client) GetEstimateFee(ctx context.Context, from, token, value str
 	return result, client.GetMethod(ctx, fmt.Sprintf("%s?%s", endpoint, params.Encode()), result)
 }
 
+// Transaction type names as reported in Transaction.TransactionType and
+// accepted by the fee estimation endpoint.
+const (
+	TransactionTypePayment = "TokenTransfer"
+	TransactionTypeMint    = "TokenMint"
+	TransactionTypeBurn    = "TokenBurn"
+)
+
+// EstimateFeeForPayment estimates the fee of sending payload from the given
+// address. Unlike GetEstimateFee, the recipient and transaction type are
+// included in the query. from is needed because an unsigned payload does not
+// identify its sender.
+func (client *Client) EstimateFeeForPayment(ctx context.Context, from common.Address, payload PaymentPayload) (*EstimateFeeResponse, error) {
+	return client.estimateFee(ctx, TransactionTypePayment, from, payload.Recipient, payload.Token, payload.Value.Int())
+}
+
+// EstimateFeeForMint estimates the fee of a mint signed by from.
+func (client *Client) EstimateFeeForMint(ctx context.Context, from common.Address, payload TokenMintPayload) (*EstimateFeeResponse, error) {
+	return client.estimateFee(ctx, TransactionTypeMint, from, payload.Recipient, payload.Token, payload.Value.Int())
+}
+
+// EstimateFeeForBurn estimates the fee of a burn signed by from.
+func (client *Client) EstimateFeeForBurn(ctx context.Context, from common.Address, payload TokenBurnPayload) (*EstimateFeeResponse, error) {
+	return client.estimateFee(ctx, TransactionTypeBurn, from, payload.Recipient, payload.Token, payload.Value.Int())
+}
+
+func (client *Client) estimateFee(ctx context.Context, txType string, from, recipient, token common.Address, value *big.Int) (*EstimateFeeResponse, error) {
+	result := new(EstimateFeeResponse)
+	if value == nil {
+		return result, fmt.Errorf("estimate fee: value is required")
+	}
+	endpoint := "/v1/transactions/estimate_fee"
+	params := url.Values{}
+	params.Set("from", from.Hex())
+	params.Set("token", token.Hex())
+	params.Set("value", value.String())
+	params.Set("recipient", recipient.Hex())
+	params.Set("transaction_type", txType)
+	return result, client.GetMethod(ctx, fmt.Sprintf("%s?%s", endpoint, params.Encode()), result)
+}
+
 type PaymentPayload struct {
 	RecentCheckpoint uint64         `json:"recent_checkpoint"`
 	ChainID          uint64         `json:"chain_id"`
 	Nonce            uint64         `json:"nonce"`
 	Recipient        common.Address `json:"recipient"`
-	Value            *big.Int       `json:"value"`
+	Value            TokenValue     `json:"value"`
 	Token            common.Address `json:"token"`
 }
 
@@ -108,5 +151,11 @@ type PaymentResponse struct {
 
 func (client *Client) SendPayment(ctx context.Context, req *PaymentRequest) (*PaymentResponse, error) {
 	result := new(PaymentResponse)
+	if err := client.CheckRecentCheckpoint(req.RecentCheckpoint); err != nil {
+		return result, err
+	}
+	if err := client.validateAmount(ctx, req.Token, req.Value.Int()); err != nil {
+		return result, err
+	}
 	return result, client.PostMethod(ctx, "/v1/transactions/payment", req, result)
 }
@@ -0,0 +1,85 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetTransactionReceipt_RevertReason(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"transaction_hash":"0xabc","success":false,"revert_reason":"insufficient balance"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	receipt, err := client.GetTransactionReceipt(context.Background(), "0xabc")
+	if err != nil {
+		t.Fatalf("GetTransactionReceipt failed: %v", err)
+	}
+	if receipt.Success {
+		t.Error("Expected Success to be false")
+	}
+	if receipt.RevertReason != "insufficient balance" {
+		t.Errorf("Expected revert reason 'insufficient balance', got '%s'", receipt.RevertReason)
+	}
+}
+
+func TestEstimateFeeForPayment(t *testing.T) {
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/transactions/estimate_fee" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query = map[string]string{}
+		for k := range r.URL.Query() {
+			query[k] = r.URL.Query().Get(k)
+		}
+		fmt.Fprintln(w, `{"fee":"25"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	from := common.HexToAddress("0x1")
+	payload := PaymentPayload{
+		Recipient: common.HexToAddress("0x2"),
+		Token:     common.HexToAddress("0x3"),
+		Value:     NewTokenValue(big.NewInt(1500000)),
+	}
+	fee, err := client.EstimateFeeForPayment(context.Background(), from, payload)
+	if err != nil {
+		t.Fatalf("EstimateFeeForPayment failed: %v", err)
+	}
+	if fee.Fee != "25" {
+		t.Errorf("Fee = %s, want 25", fee.Fee)
+	}
+	want := map[string]string{
+		"from":             from.Hex(),
+		"recipient":        payload.Recipient.Hex(),
+		"token":            payload.Token.Hex(),
+		"value":            "1500000",
+		"transaction_type": TransactionTypePayment,
+	}
+	if fmt.Sprint(query) != fmt.Sprint(want) {
+		t.Errorf("query = %v, want %v", query, want)
+	}
+
+	if _, err := client.EstimateFeeForBurn(context.Background(), from, TokenBurnPayload{Token: payload.Token, Value: NewTokenValue(big.NewInt(1))}); err != nil {
+		t.Fatalf("EstimateFeeForBurn failed: %v", err)
+	}
+	if query["transaction_type"] != TransactionTypeBurn {
+		t.Errorf("transaction_type = %s, want %s", query["transaction_type"], TransactionTypeBurn)
+	}
+
+	if _, err := client.EstimateFeeForMint(context.Background(), from, TokenMintPayload{}); err == nil {
+		t.Error("expected error for nil value")
+	}
+}
@@ -143,7 +143,7 @@ func TestSendPayment(t *testing.T) {
 		ChainID:          1212101,
 		Nonce:            nonce,
 		Recipient:        common.HexToAddress(onemoney.Test2ndAddress),
-		Value:            big.NewInt(4025),
+		Value:            onemoney.NewTokenValue(big.NewInt(4025)),
 		Token:            common.HexToAddress(onemoney.TestTokenAddress),
 	}
 	// Sign the payload
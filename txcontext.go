@@ -0,0 +1,27 @@
+package onemoney
+
+import "context"
+
+// TxContext describes where a transaction came from, for correlating requests
+// across logs and hooks.
+type TxContext struct {
+	TraceID     string
+	WalletIndex string
+	Operation   string
+}
+
+type txContextCtxKey struct{}
+
+// WithTxContext returns a copy of ctx carrying tc. The context passed to the
+// client's methods reaches PreRequest and PostRequest, so hooks can read tc
+// back with TxContextFromContext to tag their logs and metrics. tc is not sent
+// to the node.
+func WithTxContext(ctx context.Context, tc TxContext) context.Context {
+	return context.WithValue(ctx, txContextCtxKey{}, tc)
+}
+
+// TxContextFromContext returns the TxContext set by WithTxContext, if any.
+func TxContextFromContext(ctx context.Context) (TxContext, bool) {
+	tc, ok := ctx.Value(txContextCtxKey{}).(TxContext)
+	return tc, ok
+}
@@ -0,0 +1,41 @@
+package onemoney
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTxContext(t *testing.T) {
+	if _, ok := TxContextFromContext(context.Background()); ok {
+		t.Error("Expected no TxContext on a bare context")
+	}
+
+	want := TxContext{TraceID: "trace-1", WalletIndex: "7", Operation: "payment"}
+	ctx := WithTxContext(context.Background(), want)
+	if got, ok := TxContextFromContext(ctx); !ok || got != want {
+		t.Errorf("TxContextFromContext = %+v, %v, want %+v", got, ok, want)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, `{"hash":"0xabc"}`)
+	}))
+	defer server.Close()
+
+	hook := newMockHook(t)
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithClientName("runner"), WithHooks(hook))
+	var result PaymentResponse
+	if err := client.PostMethod(ctx, "/v1/transactions/payment", struct{}{}, &result); err != nil {
+		t.Fatalf("PostMethod failed: %v", err)
+	}
+	calls := hook.getPostRequestCalls()
+	if len(calls) != 1 {
+		t.Fatalf("Expected 1 PostRequest call, got %d", len(calls))
+	}
+	if got, ok := TxContextFromContext(calls[0].ctx); !ok || got != want {
+		t.Errorf("hook TxContext = %+v, %v, want %+v", got, ok, want)
+	}
+}
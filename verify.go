@@ -0,0 +1,79 @@
+package onemoney
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultCheckpointSearchSpan is how many checkpoints after its RecentCheckpoint
+// a transaction is expected to be included in.
+const DefaultCheckpointSearchSpan = 10
+
+// PendingTransaction identifies a submitted transaction to verify.
+type PendingTransaction struct {
+	Hash             string
+	RecentCheckpoint uint64
+}
+
+// CheckpointBatchVerifier confirms many transactions by reading whole checkpoints
+// instead of fetching one receipt per transaction. Transactions are grouped by
+// RecentCheckpoint, each checkpoint in [RecentCheckpoint, RecentCheckpoint+Span]
+// is fetched once, and only transactions not found in their window fall back
+// to GetTransactionReceipt, since a transaction can land later than Span
+// checkpoints after its RecentCheckpoint.
+type CheckpointBatchVerifier struct {
+	client *Client
+	// Span is the number of checkpoints after RecentCheckpoint to search.
+	Span uint64
+}
+
+// NewCheckpointBatchVerifier creates a verifier searching DefaultCheckpointSearchSpan
+// checkpoints per transaction.
+func NewCheckpointBatchVerifier(client *Client) *CheckpointBatchVerifier {
+	return &CheckpointBatchVerifier{client: client, Span: DefaultCheckpointSearchSpan}
+}
+
+// Verify returns, for every transaction hash in txs, whether it was found on chain.
+func (v *CheckpointBatchVerifier) Verify(ctx context.Context, txs []PendingTransaction) (map[string]bool, error) {
+	latestResp, err := v.client.GetCheckpointNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	latest := uint64(latestResp.Number)
+
+	checkpoints := make(map[uint64]struct{})
+	for _, tx := range txs {
+		for c := tx.RecentCheckpoint; c <= tx.RecentCheckpoint+v.Span && c <= latest; c++ {
+			checkpoints[c] = struct{}{}
+		}
+	}
+
+	included := make(map[string]bool)
+	for c := range checkpoints {
+		detail, err := v.client.GetCheckpointByNumber(ctx, int(c))
+		if err != nil {
+			return nil, err
+		}
+		for _, hash := range detail.Transactions {
+			included[strings.ToLower(hash)] = true
+		}
+	}
+
+	verified := make(map[string]bool, len(txs))
+	for _, tx := range txs {
+		if included[strings.ToLower(tx.Hash)] {
+			verified[tx.Hash] = true
+			continue
+		}
+		verified[tx.Hash] = false
+		receipt, err := v.client.GetTransactionReceipt(ctx, tx.Hash)
+		if err != nil {
+			if isReceiptPending(err) {
+				continue
+			}
+			return nil, err
+		}
+		verified[tx.Hash] = receipt.TransactionHash != ""
+	}
+	return verified, nil
+}
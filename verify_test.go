@@ -0,0 +1,95 @@
+package onemoney
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCheckpointBatchVerifier(t *testing.T) {
+	// 1000 transactions spread across checkpoints 50-54, plus two recent
+	// transactions in a checkpoint newer than the latest finalized one.
+	const latest = 100
+	byCheckpoint := make(map[int][]string)
+	var txs []PendingTransaction
+	for i := 0; i < 1000; i++ {
+		checkpoint := 50 + i%5
+		hash := fmt.Sprintf("0x%064x", i)
+		byCheckpoint[checkpoint] = append(byCheckpoint[checkpoint], hash)
+		txs = append(txs, PendingTransaction{Hash: hash, RecentCheckpoint: uint64(checkpoint)})
+	}
+	missing := fmt.Sprintf("0x%064x", 5000)
+	txs = append(txs, PendingTransaction{Hash: missing, RecentCheckpoint: 52})
+	// Included well after its window, e.g. signed against a cached checkpoint.
+	late := fmt.Sprintf("0x%064x", 5001)
+	txs = append(txs, PendingTransaction{Hash: late, RecentCheckpoint: 50})
+	recentFound := fmt.Sprintf("0x%064x", 6000)
+	recentPending := fmt.Sprintf("0x%064x", 6001)
+	txs = append(txs,
+		PendingTransaction{Hash: recentFound, RecentCheckpoint: latest - 1},
+		PendingTransaction{Hash: recentPending, RecentCheckpoint: latest - 1},
+	)
+
+	var checkpointCalls, receiptCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/checkpoints/number":
+			fmt.Fprintf(w, `{"number":%d}`, latest)
+		case "/v1/checkpoints/by_number":
+			atomic.AddInt32(&checkpointCalls, 1)
+			number, _ := strconv.Atoi(r.URL.Query().Get("number"))
+			hashes := byCheckpoint[number]
+			if hashes == nil {
+				hashes = []string{}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"number": number, "transactions": hashes})
+		case "/v1/transactions/receipt/by_hash":
+			atomic.AddInt32(&receiptCalls, 1)
+			if hash := r.URL.Query().Get("hash"); hash == recentFound || hash == late {
+				fmt.Fprintf(w, `{"transaction_hash":"%s","success":true}`, hash)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND","message":"receipt not found"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	verifier := NewCheckpointBatchVerifier(client)
+	verifier.Span = 2
+	verified, err := verifier.Verify(context.Background(), txs)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	confirmed := 0
+	for _, ok := range verified {
+		if ok {
+			confirmed++
+		}
+	}
+	if confirmed != 1002 {
+		t.Errorf("Expected 1002 confirmed transactions, got %d", confirmed)
+	}
+	if verified[missing] || verified[recentPending] || !verified[recentFound] || !verified[late] {
+		t.Errorf("Unexpected verification for edge cases: missing=%v recentPending=%v recentFound=%v late=%v",
+			verified[missing], verified[recentPending], verified[recentFound], verified[late])
+	}
+	// Checkpoints 50-56 and 99-100 are read once each. Only the four
+	// transactions not found in their window fall back to receipts.
+	if checkpointCalls != 9 {
+		t.Errorf("Expected 9 checkpoint reads, got %d", checkpointCalls)
+	}
+	if receiptCalls != 4 {
+		t.Errorf("Expected 4 receipt fallbacks, got %d", receiptCalls)
+	}
+}
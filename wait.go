@@ -0,0 +1,205 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ErrReceiptTimeout is returned by WaitForReceipt when the receipt did not appear
+// within WaitOpts.MaxRetries polls.
+var ErrReceiptTimeout = errors.New("timed out waiting for transaction receipt")
+
+// ErrConfirmationTimeout is returned by WaitForConfirmations when the receipt's
+// checkpoint did not get deep enough within WaitOpts.MaxRetries polls.
+var ErrConfirmationTimeout = errors.New("timed out waiting for confirmations")
+
+// WaitOpts configures how WaitForReceipt polls. Zero fields take the defaults
+// from DefaultWaitOpts.
+type WaitOpts struct {
+	// Interval is the delay before the second poll.
+	Interval time.Duration
+	// MaxInterval caps the delay between polls as it grows.
+	MaxInterval time.Duration
+	// Multiplier grows the delay after every poll; 1 keeps it fixed.
+	Multiplier float64
+	// MaxRetries is the number of polls after the first before giving up.
+	// Zero takes the default; use WaitNoRetries to poll only once.
+	MaxRetries int
+}
+
+// WaitNoRetries is a WaitOpts.MaxRetries value that makes the wait helpers
+// poll once and give up, since zero means "use the default".
+const WaitNoRetries = -1
+
+// DefaultWaitOpts returns a capped exponential backoff starting at 500ms and
+// growing by 1.5x up to 5s, for up to 60 retries (a little over four minutes).
+func DefaultWaitOpts() WaitOpts {
+	return WaitOpts{
+		Interval:    500 * time.Millisecond,
+		MaxInterval: 5 * time.Second,
+		Multiplier:  1.5,
+		MaxRetries:  60,
+	}
+}
+
+func (opts WaitOpts) withDefaults() WaitOpts {
+	defaults := DefaultWaitOpts()
+	if opts.Interval <= 0 {
+		opts.Interval = defaults.Interval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = defaults.MaxInterval
+	}
+	if opts.MaxInterval < opts.Interval {
+		opts.MaxInterval = opts.Interval
+	}
+	if opts.Multiplier < 1 {
+		opts.Multiplier = defaults.Multiplier
+	}
+	switch {
+	case opts.MaxRetries == 0:
+		opts.MaxRetries = defaults.MaxRetries
+	case opts.MaxRetries < 0:
+		opts.MaxRetries = 0
+	}
+	return opts
+}
+
+// WaitOption adjusts the WaitOpts used by the *AndWait helpers.
+type WaitOption func(*WaitOpts)
+
+// WithWaitOpts replaces the polling configuration wholesale.
+func WithWaitOpts(opts WaitOpts) WaitOption {
+	return func(o *WaitOpts) {
+		*o = opts
+	}
+}
+
+// WithPollInterval sets the delay before the second poll.
+func WithPollInterval(d time.Duration) WaitOption {
+	return func(o *WaitOpts) {
+		o.Interval = d
+	}
+}
+
+// WithMaxPolls sets how many polls are made after the first before giving up.
+// n <= 0 polls only once.
+func WithMaxPolls(n int) WaitOption {
+	return func(o *WaitOpts) {
+		if n <= 0 {
+			n = WaitNoRetries
+		}
+		o.MaxRetries = n
+	}
+}
+
+func applyWaitOptions(opts []WaitOption) WaitOpts {
+	waitOpts := DefaultWaitOpts()
+	for _, opt := range opts {
+		opt(&waitOpts)
+	}
+	return waitOpts
+}
+
+// WaitForReceipt polls GetTransactionReceipt until the receipt for hash is
+// available, backing off between polls as configured by opts. A not-found
+// response or a network error counts as "not yet"; any other error, including
+// an undecodable response or an exhausted retry budget, is returned
+// immediately.
+func (client *Client) WaitForReceipt(ctx context.Context, hash string, opts WaitOpts) (*TransactionReceiptResponse, error) {
+	opts = opts.withDefaults()
+	interval := opts.Interval
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := client.attemptContext(ctx, attempt)
+		receipt, err := client.GetTransactionReceipt(attemptCtx, hash)
+		cancel()
+		if err == nil {
+			return receipt, nil
+		}
+		if !isReceiptPending(err) && !isTransientPollError(err) {
+			return nil, err
+		}
+		lastErr = err
+		if attempt >= opts.MaxRetries {
+			return nil, fmt.Errorf("%w %s after %d retries: %v", ErrReceiptTimeout, hash, attempt, lastErr)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// WaitForConfirmations waits for the receipt of hash, then polls
+// GetCheckpointNumber until the receipt's checkpoint is at least n checkpoints
+// behind the latest. n <= 0 behaves like WaitForReceipt. opts applies to both
+// phases. On ErrConfirmationTimeout the receipt is returned with the error.
+func (client *Client) WaitForConfirmations(ctx context.Context, hash string, n int, opts WaitOpts) (*TransactionReceiptResponse, error) {
+	receipt, err := client.WaitForReceipt(ctx, hash, opts)
+	if err != nil || n <= 0 {
+		return receipt, err
+	}
+	opts = opts.withDefaults()
+	target := receipt.CheckpointNumber + n
+	interval := opts.Interval
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := client.attemptContext(ctx, attempt)
+		latest, err := client.GetCheckpointNumber(attemptCtx)
+		cancel()
+		if err == nil && latest.Number >= target {
+			return receipt, nil
+		}
+		if attempt >= opts.MaxRetries {
+			if err != nil {
+				return receipt, fmt.Errorf("%w: %s after %d retries: %v", ErrConfirmationTimeout, hash, attempt, err)
+			}
+			return receipt, fmt.Errorf("%w: %s at checkpoint %d, latest %d, want %d confirmations",
+				ErrConfirmationTimeout, hash, receipt.CheckpointNumber, latest.Number, n)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return receipt, ctx.Err()
+		case <-timer.C:
+		}
+		interval = time.Duration(float64(interval) * opts.Multiplier)
+		if interval > opts.MaxInterval {
+			interval = opts.MaxInterval
+		}
+	}
+}
+
+// isReceiptPending reports whether err is the node saying it has no receipt
+// yet: a 404 or a not-found API error.
+func isReceiptPending(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.StatusCode == http.StatusNotFound || IsNotFound(err) || IsTransactionNotFound(err)
+}
+
+// isTransientPollError reports whether err is a network failure that a polling
+// loop should ride out. An exhausted retry budget is not, even though it wraps
+// the network error that used it up.
+func isTransientPollError(err error) bool {
+	if errors.Is(err, ErrRetryBudgetExhausted) {
+		return false
+	}
+	var netErr *NetworkError
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,167 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWaitForReceipt_AppearsOnThirtiethPoll(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&polls, 1) < 30 {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, `{"error_code":"NOT_FOUND","message":"receipt not found"}`)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, `{"transaction_hash":"0xabc","success":true,"checkpoint_number":42}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	opts := WaitOpts{Interval: time.Millisecond, MaxInterval: 5 * time.Millisecond, Multiplier: 2, MaxRetries: 40}
+	receipt, err := client.WaitForReceipt(context.Background(), "0xabc", opts)
+	if err != nil {
+		t.Fatalf("WaitForReceipt failed: %v", err)
+	}
+	if receipt.CheckpointNumber != 42 || !receipt.Success {
+		t.Errorf("Unexpected receipt: %+v", receipt)
+	}
+	if polls != 30 {
+		t.Errorf("Expected 30 polls, got %d", polls)
+	}
+}
+
+func TestWaitForReceipt_GivesUp(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&polls, 1)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error_code":"NOT_FOUND","message":"receipt not found"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	opts := WaitOpts{Interval: time.Millisecond, Multiplier: 1, MaxRetries: 5}
+	_, err := client.WaitForReceipt(context.Background(), "0xabc", opts)
+	if !errors.Is(err, ErrReceiptTimeout) {
+		t.Fatalf("Expected ErrReceiptTimeout, got %v", err)
+	}
+	if polls != 6 {
+		t.Errorf("Expected 6 polls (1 + 5 retries), got %d", polls)
+	}
+}
+
+func TestWaitForReceipt_NoRetries(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&polls, 1)
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintln(w, `{"error_code":"NOT_FOUND","message":"receipt not found"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	_, err := client.WaitForReceipt(context.Background(), "0xabc", WaitOpts{Interval: time.Millisecond, MaxRetries: WaitNoRetries})
+	if !errors.Is(err, ErrReceiptTimeout) {
+		t.Fatalf("Expected ErrReceiptTimeout, got %v", err)
+	}
+	if polls != 1 {
+		t.Errorf("Expected a single poll, got %d", polls)
+	}
+}
+
+func TestWaitForReceipt_DecodeError(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&polls, 1)
+		fmt.Fprintln(w, `not json`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	_, err := client.WaitForReceipt(context.Background(), "0xabc", WaitOpts{Interval: time.Millisecond, Multiplier: 1, MaxRetries: 5})
+	if err == nil || errors.Is(err, ErrReceiptTimeout) {
+		t.Fatalf("Expected the decode error to be returned, got %v", err)
+	}
+	if polls != 1 {
+		t.Errorf("Expected a decode error to stop polling, got %d polls", polls)
+	}
+}
+
+func TestIsTransientPollError(t *testing.T) {
+	netErr := &NetworkError{Kind: NetworkErrorTimeout, Method: "GET", Path: "/", Err: context.DeadlineExceeded}
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{netErr, true},
+		{fmt.Errorf("%w, not failing over: %w", ErrRetryBudgetExhausted, netErr), false},
+		{errors.New("invalid character 'o' in literal null"), false},
+		{&APIError{StatusCode: http.StatusNotFound}, false},
+	} {
+		if got := isTransientPollError(tc.err); got != tc.want {
+			t.Errorf("isTransientPollError(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestWaitForReceipt_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintln(w, `{"error_code":"BAD_INPUT","message":"invalid hash"}`)
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	_, err := client.WaitForReceipt(context.Background(), "bogus", WaitOpts{Interval: time.Millisecond})
+	if !IsErrorCode(err, ErrCodeBadInput) {
+		t.Fatalf("Expected BAD_INPUT API error to be returned immediately, got %v", err)
+	}
+}
+
+func TestWaitForConfirmations(t *testing.T) {
+	var checkpoint atomic.Int32
+	checkpoint.Store(42)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/transactions/receipt/by_hash":
+			fmt.Fprintln(w, `{"transaction_hash":"0xabc","success":true,"checkpoint_number":42}`)
+		case "/v1/checkpoints/number":
+			// Advance one checkpoint per poll.
+			fmt.Fprintf(w, `{"number":%d}`, checkpoint.Add(1)-1)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	opts := WaitOpts{Interval: time.Millisecond, Multiplier: 1, MaxRetries: 10}
+	receipt, err := client.WaitForConfirmations(context.Background(), "0xabc", 3, opts)
+	if err != nil {
+		t.Fatalf("WaitForConfirmations failed: %v", err)
+	}
+	if receipt.CheckpointNumber != 42 {
+		t.Errorf("Unexpected receipt: %+v", receipt)
+	}
+	// Polls saw 42, 43, 44 and 45; 45 is three checkpoints past the receipt.
+	if got := checkpoint.Load(); got != 46 {
+		t.Errorf("Expected 4 checkpoint polls, got %d", got-42)
+	}
+
+	checkpoint.Store(0)
+	receipt, err = client.WaitForConfirmations(context.Background(), "0xabc", 100, WaitOpts{Interval: time.Millisecond, Multiplier: 1, MaxRetries: 2})
+	if !errors.Is(err, ErrConfirmationTimeout) {
+		t.Fatalf("Expected ErrConfirmationTimeout, got %v", err)
+	}
+	if receipt == nil || receipt.TransactionHash != "0xabc" {
+		t.Errorf("Expected the receipt alongside the timeout, got %+v", receipt)
+	}
+}
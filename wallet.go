@@ -0,0 +1,234 @@
+package onemoney
+
+import (
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultDerivationPath is the BIP-44 path of the first Ethereum account, used
+// when WalletFromMnemonic is given an empty path.
+const DefaultDerivationPath = "m/44'/60'/0'/0/0"
+
+const hardenedKeyOffset = 0x80000000
+
+// ErrInvalidMnemonic is returned by WalletFromMnemonic for a mnemonic with the
+// wrong number of words, a word outside the BIP-39 English wordlist, or a bad
+// checksum.
+var ErrInvalidMnemonic = errors.New("invalid mnemonic")
+
+//go:embed bip39_english.txt
+var bip39English string
+
+// bip39WordIndex maps each word of the BIP-39 English wordlist to its index.
+var bip39WordIndex = sync.OnceValue(func() map[string]int {
+	words := strings.Fields(bip39English)
+	index := make(map[string]int, len(words))
+	for i, word := range words {
+		index[word] = i
+	}
+	return index
+})
+
+// Wallet holds a private key together with its address. PrivateKey is a
+// 0x-prefixed hex string accepted by SignMessage and NewLocalSigner.
+type Wallet struct {
+	PrivateKey string
+	Address    string
+	// Path is the derivation path for wallets from WalletFromMnemonic, and
+	// empty otherwise.
+	Path string
+}
+
+// NewRandomWallet generates a wallet with a fresh random key.
+func NewRandomWallet() (*Wallet, error) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	return &Wallet{
+		PrivateKey: hexutil.Encode(crypto.FromECDSA(key)),
+		Address:    crypto.PubkeyToAddress(key.PublicKey).Hex(),
+	}, nil
+}
+
+// WalletFromPrivateKey wraps a hex private key, with or without 0x prefix.
+func WalletFromPrivateKey(privateKey string) (*Wallet, error) {
+	signer, err := NewLocalSigner(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		PrivateKey: hexutil.Encode(crypto.FromECDSA(signer.key)),
+		Address:    signer.Address().Hex(),
+	}, nil
+}
+
+// Sign signs payload with the wallet's key the same way as SignMessage.
+func (w *Wallet) Sign(payload interface{}) (*Signature, error) {
+	signer, err := NewLocalSigner(w.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return SignMessageWithSigner(payload, signer)
+}
+
+// WalletFromMnemonic derives the key at path from a BIP-39 mnemonic (empty
+// passphrase) using BIP-32 derivation. The mnemonic must be English: every word
+// is checked against the BIP-39 wordlist and the checksum is verified, so a
+// mistyped mnemonic returns ErrInvalidMnemonic instead of a different wallet.
+func WalletFromMnemonic(mnemonic string, path string) (*Wallet, error) {
+	if path == "" {
+		path = DefaultDerivationPath
+	}
+	indices, err := parseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := mnemonicSeed(mnemonic)
+	if err != nil {
+		return nil, err
+	}
+	key, err := deriveKey(seed, indices)
+	if err != nil {
+		return nil, fmt.Errorf("derive %s: %w", path, err)
+	}
+	priv, err := crypto.ToECDSA(key)
+	if err != nil {
+		return nil, fmt.Errorf("derive %s: %w", path, err)
+	}
+	return &Wallet{
+		PrivateKey: hexutil.Encode(crypto.FromECDSA(priv)),
+		Address:    crypto.PubkeyToAddress(priv.PublicKey).Hex(),
+		Path:       path,
+	}, nil
+}
+
+// WalletsFromMnemonic derives count wallets at m/44'/60'/0'/0/i for i starting
+// at start.
+func WalletsFromMnemonic(mnemonic string, start, count int) ([]*Wallet, error) {
+	if start < 0 || count < 0 {
+		return nil, errors.New("start and count must be non-negative")
+	}
+	wallets := make([]*Wallet, 0, count)
+	for i := start; i < start+count; i++ {
+		w, err := WalletFromMnemonic(mnemonic, fmt.Sprintf("m/44'/60'/0'/0/%d", i))
+		if err != nil {
+			return nil, err
+		}
+		wallets = append(wallets, w)
+	}
+	return wallets, nil
+}
+
+// mnemonicSeed validates mnemonic and returns its BIP-39 seed. The English
+// wordlist is ASCII, so a mnemonic that passes validation is already in NFKD
+// form and needs no further normalisation.
+func mnemonicSeed(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if err := validateMnemonic(words); err != nil {
+		return nil, err
+	}
+	return pbkdf2.Key(sha512.New, strings.Join(words, " "), []byte("mnemonic"), 2048, 64)
+}
+
+// validateMnemonic checks words against the BIP-39 English wordlist and
+// checksum. Errors name word positions, never the words, which are secret.
+func validateMnemonic(words []string) error {
+	switch len(words) {
+	case 12, 15, 18, 21, 24:
+	default:
+		return fmt.Errorf("%w: expected 12, 15, 18, 21 or 24 words, got %d", ErrInvalidMnemonic, len(words))
+	}
+	index := bip39WordIndex()
+	bits := new(big.Int)
+	for i, word := range words {
+		n, ok := index[word]
+		if !ok {
+			return fmt.Errorf("%w: word %d is not in the BIP-39 English wordlist", ErrInvalidMnemonic, i+1)
+		}
+		bits.Lsh(bits, 11).Or(bits, big.NewInt(int64(n)))
+	}
+	// Every 33 bits of the mnemonic hold 32 bits of entropy and 1 of checksum.
+	checksumBits := uint(len(words) * 11 / 33)
+	checksum := new(big.Int).And(bits, big.NewInt(1<<checksumBits-1)).Uint64()
+	entropy := new(big.Int).Rsh(bits, checksumBits).FillBytes(make([]byte, checksumBits*4))
+	if want := uint64(sha256.Sum256(entropy)[0] >> (8 - checksumBits)); checksum != want {
+		return fmt.Errorf("%w: checksum mismatch", ErrInvalidMnemonic)
+	}
+	return nil
+}
+
+func parseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(strings.TrimSpace(path), "/")
+	if len(parts) < 2 || parts[0] != "m" {
+		return nil, fmt.Errorf("invalid derivation path %q", path)
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		var offset uint32
+		if strings.HasSuffix(part, "'") || strings.HasSuffix(part, "h") {
+			offset = hardenedKeyOffset
+			part = part[:len(part)-1]
+		}
+		n, err := strconv.ParseUint(part, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid derivation path %q: %w", path, err)
+		}
+		indices = append(indices, uint32(n)+offset)
+	}
+	return indices, nil
+}
+
+// deriveKey walks the BIP-32 private derivation from the master key of seed.
+func deriveKey(seed []byte, indices []uint32) ([]byte, error) {
+	n := crypto.S256().Params().N
+
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	key, chainCode := sum[:32], sum[32:]
+
+	for _, index := range indices {
+		var data []byte
+		if index >= hardenedKeyOffset {
+			data = append([]byte{0}, key...)
+		} else {
+			priv, err := crypto.ToECDSA(key)
+			if err != nil {
+				return nil, err
+			}
+			data = crypto.CompressPubkey(&priv.PublicKey)
+		}
+		data = binary.BigEndian.AppendUint32(data, index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		sum := mac.Sum(nil)
+
+		il := new(big.Int).SetBytes(sum[:32])
+		if il.Cmp(n) >= 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		child := il.Add(il, new(big.Int).SetBytes(key))
+		child.Mod(child, n)
+		if child.Sign() == 0 {
+			return nil, fmt.Errorf("invalid child key at index %d", index)
+		}
+		key = child.FillBytes(make([]byte, 32))
+		chainCode = sum[32:]
+	}
+	return key, nil
+}
@@ -0,0 +1,122 @@
+package onemoney
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestWalletFromMnemonic(t *testing.T) {
+	w, err := WalletFromMnemonic(testMnemonic, "")
+	if err != nil {
+		t.Fatalf("WalletFromMnemonic failed: %v", err)
+	}
+	// Well-known first account of the BIP-39 test mnemonic.
+	if w.Address != "0x9858EfFD232B4033E47d90003D41EC34EcaEda94" {
+		t.Errorf("Address = %s", w.Address)
+	}
+	if w.Path != DefaultDerivationPath {
+		t.Errorf("Path = %s", w.Path)
+	}
+	addr, err := PrivateKeyToAddress(w.PrivateKey)
+	if err != nil || addr != w.Address {
+		t.Errorf("PrivateKeyToAddress(%s) = %s, %v", w.PrivateKey, addr, err)
+	}
+
+	// The 24-word mnemonic for all-zero entropy has an 8-bit checksum.
+	if _, err := WalletFromMnemonic(strings.Repeat("abandon ", 23)+"art", ""); err != nil {
+		t.Errorf("valid 24-word mnemonic rejected: %v", err)
+	}
+
+	// Extra whitespace does not change the derived key.
+	spaced, err := WalletFromMnemonic("  "+strings.ReplaceAll(testMnemonic, " ", "\t "), DefaultDerivationPath)
+	if err != nil || spaced.Address != w.Address {
+		t.Errorf("whitespace-normalised mnemonic gave %v, %v", spaced, err)
+	}
+}
+
+func TestWalletsFromMnemonic(t *testing.T) {
+	wallets, err := WalletsFromMnemonic(testMnemonic, 0, 3)
+	if err != nil {
+		t.Fatalf("WalletsFromMnemonic failed: %v", err)
+	}
+	if len(wallets) != 3 {
+		t.Fatalf("got %d wallets", len(wallets))
+	}
+	if wallets[0].Address != "0x9858EfFD232B4033E47d90003D41EC34EcaEda94" {
+		t.Errorf("wallets[0] = %s", wallets[0].Address)
+	}
+	if wallets[1].Address != "0x6Fac4D18c912343BF86fa7049364Dd4E424Ab9C0" {
+		t.Errorf("wallets[1] = %s", wallets[1].Address)
+	}
+	if wallets[2].Path != "m/44'/60'/0'/0/2" {
+		t.Errorf("wallets[2].Path = %s", wallets[2].Path)
+	}
+}
+
+func TestWalletFromMnemonic_Invalid(t *testing.T) {
+	for name, mnemonic := range map[string]string{
+		"short":         "abandon about",
+		"checksum":      strings.Repeat("abandon ", 12),
+		"unknown word":  strings.Replace(testMnemonic, "about", "abuot", 1),
+		"not lowercase": strings.Replace(testMnemonic, "about", "About", 1),
+	} {
+		_, err := WalletFromMnemonic(mnemonic, "")
+		if !errors.Is(err, ErrInvalidMnemonic) {
+			t.Errorf("%s: expected ErrInvalidMnemonic, got %v", name, err)
+		} else if strings.Contains(err.Error(), "abuot") {
+			t.Errorf("%s: error leaks a mnemonic word: %v", name, err)
+		}
+	}
+	for _, path := range []string{"44'/60'", "m/x", "m/44'/-1", "m/2147483648"} {
+		if _, err := WalletFromMnemonic(testMnemonic, path); err == nil {
+			t.Errorf("expected error for path %q", path)
+		}
+	}
+}
+
+func TestNewRandomWallet(t *testing.T) {
+	a, err := NewRandomWallet()
+	if err != nil {
+		t.Fatalf("NewRandomWallet failed: %v", err)
+	}
+	b, _ := NewRandomWallet()
+	if a.Address == b.Address {
+		t.Error("two random wallets share an address")
+	}
+	if addr, err := PrivateKeyToAddress(a.PrivateKey); err != nil || addr != a.Address {
+		t.Errorf("PrivateKeyToAddress = %s, %v; want %s", addr, err, a.Address)
+	}
+}
+
+func TestWalletFromPrivateKey(t *testing.T) {
+	w, err := WalletFromPrivateKey(strings.TrimPrefix(testPrivateKey, "0x"))
+	if err != nil {
+		t.Fatalf("WalletFromPrivateKey failed: %v", err)
+	}
+	if w.PrivateKey != testPrivateKey {
+		t.Errorf("PrivateKey = %s, want %s", w.PrivateKey, testPrivateKey)
+	}
+	if want, _ := PrivateKeyToAddress(testPrivateKey); w.Address != want {
+		t.Errorf("Address = %s, want %s", w.Address, want)
+	}
+
+	payload := PaymentPayload{ChainID: 1212101, Nonce: 2, Value: NewTokenValue(big.NewInt(10)), Token: common.HexToAddress("0x3")}
+	sig, err := w.Sign(payload)
+	if err != nil {
+		t.Fatalf("Sign failed: %v", err)
+	}
+	want, _ := NewTestClient().SignMessage(payload, testPrivateKey)
+	if *sig != *want {
+		t.Errorf("Sign = %+v, want %+v", sig, want)
+	}
+
+	if _, err := WalletFromPrivateKey("0xnothex"); err == nil {
+		t.Error("expected error for invalid key")
+	}
+}
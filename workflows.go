@@ -0,0 +1,188 @@
+package onemoney
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrTransactionFailed is returned by the *AndWait helpers when the transaction
+// was included but its receipt reports failure.
+var ErrTransactionFailed = errors.New("transaction failed on chain")
+
+// IssueParams describes a token to issue with IssueTokenAndWait.
+type IssueParams struct {
+	Symbol   string
+	Name     string
+	Decimals uint8
+	// MasterAuthority defaults to the signer's address when zero.
+	MasterAuthority common.Address
+	IsPrivate       bool
+	// ChainID is fetched from the node when zero.
+	ChainID uint64
+}
+
+// IssueTokenAndWait issues a token in one call: it fetches the signer's nonce and
+// the latest checkpoint, signs and submits the TokenIssuePayload, waits for the
+// receipt and returns the confirmed token address.
+func (client *Client) IssueTokenAndWait(ctx context.Context, params IssueParams, signer Signer, waitOpts WaitOpts) (common.Address, error) {
+	token, _, err := client.issueTokenAndWait(ctx, params, signer, waitOpts)
+	return token, err
+}
+
+// issueTokenAndWait implements IssueTokenAndWait and also returns the issue
+// transaction hash.
+func (client *Client) issueTokenAndWait(ctx context.Context, params IssueParams, signer Signer, waitOpts WaitOpts) (common.Address, string, error) {
+	chainID, nonce, checkpoint, err := client.signingContext(ctx, params.ChainID, signer.Address())
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	master := params.MasterAuthority
+	if master == (common.Address{}) {
+		master = signer.Address()
+	}
+	payload := TokenIssuePayload{
+		RecentCheckpoint: checkpoint,
+		ChainID:          chainID,
+		Nonce:            nonce,
+		Symbol:           params.Symbol,
+		Name:             params.Name,
+		Decimals:         params.Decimals,
+		MasterAuthority:  master,
+		IsPrivate:        params.IsPrivate,
+	}
+	signature, err := SignMessageWithSigner(payload, signer)
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	resp, err := client.IssueToken(ctx, &IssueTokenRequest{TokenIssuePayload: payload, Signature: *signature})
+	if err != nil {
+		return common.Address{}, "", err
+	}
+	receipt, err := client.waitForSuccess(ctx, resp.Hash, waitOpts)
+	if err != nil {
+		return common.Address{}, resp.Hash, err
+	}
+	token := receipt.TokenAddress
+	if token == "" {
+		token = resp.Token
+	}
+	if !common.IsHexAddress(token) {
+		return common.Address{}, resp.Hash, fmt.Errorf("issue token %s: no token address in receipt or response", resp.Hash)
+	}
+	return common.HexToAddress(token), resp.Hash, nil
+}
+
+// signingContext returns the chain id (fetched when chainID is zero), the next
+// nonce of address and RecentCheckpoint, as needed to build a payload.
+func (client *Client) signingContext(ctx context.Context, chainID uint64, address common.Address) (uint64, uint64, uint64, error) {
+	if chainID == 0 {
+		chain, err := client.GetChainId(ctx)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("get chain id: %w", err)
+		}
+		chainID = uint64(chain.ChainId)
+	}
+	accountNonce, err := client.GetAccountNonce(ctx, address.Hex())
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("get account nonce: %w", err)
+	}
+	checkpoint, err := client.RecentCheckpoint(ctx)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("get checkpoint number: %w", err)
+	}
+	return chainID, accountNonce.Nonce, checkpoint, nil
+}
+
+// waitForSuccess waits for the receipt of hash and turns a failed receipt into
+// an ErrTransactionFailed error.
+func (client *Client) waitForSuccess(ctx context.Context, hash string, opts WaitOpts) (*TransactionReceiptResponse, error) {
+	receipt, err := client.WaitForReceipt(ctx, hash, opts)
+	if err != nil {
+		return nil, err
+	}
+	if !receipt.Success {
+		if receipt.RevertReason != "" {
+			return receipt, fmt.Errorf("%w: %s: %s", ErrTransactionFailed, hash, receipt.RevertReason)
+		}
+		return receipt, fmt.Errorf("%w: %s", ErrTransactionFailed, hash)
+	}
+	return receipt, nil
+}
+
+// ConfirmedPayment is the result of SendPaymentAndWait.
+type ConfirmedPayment struct {
+	PaymentResponse
+	Receipt *TransactionReceiptResponse
+	// ConfirmationLatency is the time from submission to the receipt being seen.
+	ConfirmationLatency time.Duration
+}
+
+// ConfirmedTransaction is the result of the token *AndWait helpers.
+type ConfirmedTransaction struct {
+	Hash    string
+	Receipt *TransactionReceiptResponse
+	// ConfirmationLatency is the time from submission to the receipt being seen.
+	ConfirmationLatency time.Duration
+}
+
+// SendPaymentAndWait submits req and waits for its receipt. A receipt that
+// reports failure is returned together with an ErrTransactionFailed error.
+// With WithTransferLockCheck, nothing is submitted while the token has a
+// transfer lock; the error wraps ErrTokenTransferLocked and names the unlock
+// checkpoint, if any.
+func (client *Client) SendPaymentAndWait(ctx context.Context, req *PaymentRequest, opts ...WaitOption) (*ConfirmedPayment, error) {
+	if err := client.checkTransferLock(ctx, req.Token.Hex()); err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := client.SendPayment(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := client.waitForSuccess(ctx, resp.Hash, applyWaitOptions(opts))
+	if receipt == nil {
+		return nil, err
+	}
+	return &ConfirmedPayment{PaymentResponse: *resp, Receipt: receipt, ConfirmationLatency: time.Since(start)}, err
+}
+
+// MintTokenAndWait submits req and waits for its receipt.
+func (client *Client) MintTokenAndWait(ctx context.Context, req *MintTokenRequest, opts ...WaitOption) (*ConfirmedTransaction, error) {
+	return client.submitAndWait(ctx, opts, func() (string, error) {
+		resp, err := client.MintToken(ctx, req)
+		return resp.Hash, err
+	})
+}
+
+// BurnTokenAndWait submits req and waits for its receipt.
+func (client *Client) BurnTokenAndWait(ctx context.Context, req *BurnTokenRequest, opts ...WaitOption) (*ConfirmedTransaction, error) {
+	return client.submitAndWait(ctx, opts, func() (string, error) {
+		resp, err := client.BurnToken(ctx, req)
+		return resp.Hash, err
+	})
+}
+
+// GrantTokenAuthorityAndWait submits req and waits for its receipt.
+func (client *Client) GrantTokenAuthorityAndWait(ctx context.Context, req *TokenAuthorityRequest, opts ...WaitOption) (*ConfirmedTransaction, error) {
+	return client.submitAndWait(ctx, opts, func() (string, error) {
+		resp, err := client.GrantTokenAuthority(ctx, req)
+		return resp.Hash, err
+	})
+}
+
+func (client *Client) submitAndWait(ctx context.Context, opts []WaitOption, submit func() (string, error)) (*ConfirmedTransaction, error) {
+	start := time.Now()
+	hash, err := submit()
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := client.waitForSuccess(ctx, hash, applyWaitOptions(opts))
+	if receipt == nil {
+		return nil, err
+	}
+	return &ConfirmedTransaction{Hash: hash, Receipt: receipt, ConfirmationLatency: time.Since(start)}, err
+}
@@ -0,0 +1,234 @@
+package onemoney
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var fastWait = WaitOpts{Interval: time.Millisecond, Multiplier: 1, MaxRetries: 5}
+
+func TestIssueTokenAndWait(t *testing.T) {
+	signer, err := NewLocalSigner(testPrivateKey)
+	if err != nil {
+		t.Fatalf("NewLocalSigner failed: %v", err)
+	}
+	token := "0x00000000000000000000000000000000000000AB"
+	var issued IssueTokenRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/chains/chain_id":
+			fmt.Fprintln(w, `{"chain_id":1212101}`)
+		case "/v1/accounts/nonce":
+			fmt.Fprintln(w, `{"nonce":7}`)
+		case "/v1/checkpoints/number":
+			fmt.Fprintln(w, `{"number":500}`)
+		case "/v1/tokens/issue":
+			if err := json.NewDecoder(r.Body).Decode(&issued); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			fmt.Fprintln(w, `{"hash":"0xissue"}`)
+		case "/v1/transactions/receipt/by_hash":
+			fmt.Fprintf(w, `{"transaction_hash":"0xissue","success":true,"token_address":"%s"}`, token)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	params := IssueParams{Symbol: "USDA", Name: "1Money Stable Coin", Decimals: 6}
+	got, err := client.IssueTokenAndWait(context.Background(), params, signer, fastWait)
+	if err != nil {
+		t.Fatalf("IssueTokenAndWait failed: %v", err)
+	}
+	if got != common.HexToAddress(token) {
+		t.Errorf("Expected token %s, got %s", token, got.Hex())
+	}
+	if issued.Nonce != 7 || issued.RecentCheckpoint != 500 || issued.ChainID != 1212101 {
+		t.Errorf("Unexpected payload: %+v", issued.TokenIssuePayload)
+	}
+	if issued.MasterAuthority != signer.Address() {
+		t.Errorf("Expected master authority to default to the signer, got %s", issued.MasterAuthority.Hex())
+	}
+	expected, _ := SignMessageWithSigner(issued.TokenIssuePayload, signer)
+	if issued.Signature != *expected {
+		t.Error("Submitted signature does not match the submitted payload")
+	}
+}
+
+func TestIssueTokenAndWait_FailedReceipt(t *testing.T) {
+	signer, _ := NewLocalSigner(testPrivateKey)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/accounts/nonce":
+			fmt.Fprintln(w, `{"nonce":0}`)
+		case "/v1/checkpoints/number":
+			fmt.Fprintln(w, `{"number":1}`)
+		case "/v1/tokens/issue":
+			fmt.Fprintln(w, `{"hash":"0xissue"}`)
+		case "/v1/transactions/receipt/by_hash":
+			fmt.Fprintln(w, `{"transaction_hash":"0xissue","success":false,"revert_reason":"symbol taken"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	params := IssueParams{Symbol: "USDA", Decimals: 6, ChainID: 1212101}
+	_, err := client.IssueTokenAndWait(context.Background(), params, signer, fastWait)
+	if !errors.Is(err, ErrTransactionFailed) {
+		t.Fatalf("Expected ErrTransactionFailed, got %v", err)
+	}
+}
+
+func TestSendPaymentAndWait(t *testing.T) {
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/transactions/payment":
+			fmt.Fprintln(w, `{"hash":"0xpay"}`)
+		case "/v1/transactions/receipt/by_hash":
+			polls++
+			if polls < 3 {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprintln(w, `{"error_code":"TRANSACTION_NOT_FOUND","message":"not yet"}`)
+				return
+			}
+			fmt.Fprintln(w, `{"transaction_hash":"0xpay","success":true,"checkpoint_number":9}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	confirmed, err := client.SendPaymentAndWait(context.Background(), &PaymentRequest{}, WithWaitOpts(fastWait))
+	if err != nil {
+		t.Fatalf("SendPaymentAndWait failed: %v", err)
+	}
+	if confirmed.Hash != "0xpay" || confirmed.Receipt == nil || confirmed.Receipt.CheckpointNumber != 9 {
+		t.Errorf("unexpected result: %+v", confirmed)
+	}
+	if confirmed.ConfirmationLatency <= 0 {
+		t.Errorf("ConfirmationLatency = %v, want > 0", confirmed.ConfirmationLatency)
+	}
+	if polls != 3 {
+		t.Errorf("polls = %d, want 3", polls)
+	}
+}
+
+func TestTokenAndWaitHelpers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/tokens/mint":
+			fmt.Fprintln(w, `{"hash":"0xmint"}`)
+		case "/v1/tokens/burn":
+			fmt.Fprintln(w, `{"hash":"0xburn"}`)
+		case "/v1/tokens/grant_authority":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, `{"error_code":"UNAUTHORIZED","message":"not master"}`)
+		case "/v1/transactions/receipt/by_hash":
+			hash := r.URL.Query().Get("hash")
+			fmt.Fprintf(w, `{"transaction_hash":%q,"success":%t,"revert_reason":"paused"}`, hash, hash == "0xmint")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second))
+	ctx := context.Background()
+
+	minted, err := client.MintTokenAndWait(ctx, &MintTokenRequest{}, WithWaitOpts(fastWait))
+	if err != nil || minted.Hash != "0xmint" || !minted.Receipt.Success {
+		t.Errorf("MintTokenAndWait = %+v, %v", minted, err)
+	}
+
+	burned, err := client.BurnTokenAndWait(ctx, &BurnTokenRequest{}, WithPollInterval(time.Millisecond), WithMaxPolls(1))
+	if !errors.Is(err, ErrTransactionFailed) {
+		t.Errorf("BurnTokenAndWait error = %v, want ErrTransactionFailed", err)
+	}
+	if burned == nil || burned.Receipt.RevertReason != "paused" {
+		t.Errorf("failed burn should still return the receipt, got %+v", burned)
+	}
+
+	granted, err := client.GrantTokenAuthorityAndWait(ctx, &TokenAuthorityRequest{}, WithWaitOpts(fastWait))
+	if !IsErrorCode(err, ErrCodeUnauthorized) || granted != nil {
+		t.Errorf("GrantTokenAuthorityAndWait = %+v, %v; want UNAUTHORIZED", granted, err)
+	}
+}
+
+func TestSendPaymentAndWait_TransferLocked(t *testing.T) {
+	lock := `{"locked":true,"unlock_at":500}`
+	payments, lockReads := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/tokens/transfer_lock":
+			lockReads++
+			if lock == "" {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			fmt.Fprintln(w, lock)
+		case "/v1/transactions/payment":
+			payments++
+			fmt.Fprintln(w, `{"hash":"0xpay"}`)
+		case "/v1/transactions/receipt/by_hash":
+			fmt.Fprintln(w, `{"transaction_hash":"0xpay","success":true,"checkpoint_number":9}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	req := &PaymentRequest{PaymentPayload: PaymentPayload{Token: common.HexToAddress("0x3")}}
+	// The check is opt-in; by default the lock is not read at all.
+	unchecked := newClientInternal(server.URL, WithTimeout(time.Second))
+	if _, err := unchecked.SendPaymentAndWait(context.Background(), req, WithWaitOpts(fastWait)); err != nil {
+		t.Fatalf("SendPaymentAndWait without the lock check failed: %v", err)
+	}
+	if lockReads != 0 || payments != 1 {
+		t.Fatalf("Expected 1 payment and no lock reads, got %d payments and %d reads", payments, lockReads)
+	}
+	payments = 0
+
+	client := newClientInternal(server.URL, WithTimeout(time.Second), WithTransferLockCheck())
+	_, err := client.SendPaymentAndWait(context.Background(), req, WithWaitOpts(fastWait))
+	if !errors.Is(err, ErrTokenTransferLocked) {
+		t.Fatalf("Expected ErrTokenTransferLocked, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "checkpoint 500") {
+		t.Errorf("Error should name the unlock checkpoint: %v", err)
+	}
+	if payments != 0 {
+		t.Errorf("Expected no payment to be submitted while locked, got %d", payments)
+	}
+
+	lock = `{"locked":false}`
+	if _, err := client.SendPaymentAndWait(context.Background(), req, WithWaitOpts(fastWait)); err != nil {
+		t.Fatalf("SendPaymentAndWait after unlock failed: %v", err)
+	}
+	if payments != 1 {
+		t.Errorf("Expected 1 payment after unlock, got %d", payments)
+	}
+
+	// A node without the lock route does not block the payment.
+	lock = ""
+	if _, err := client.SendPaymentAndWait(context.Background(), req, WithWaitOpts(fastWait)); err != nil {
+		t.Fatalf("SendPaymentAndWait with an unreadable lock failed: %v", err)
+	}
+	if payments != 2 {
+		t.Errorf("Expected 2 payments, got %d", payments)
+	}
+}